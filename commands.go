@@ -0,0 +1,494 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+
+	. "Notifier/models"
+	. "Notifier/src/notifiers"
+	. "Notifier/src/sinks"
+	. "Notifier/src/store"
+	. "Notifier/src/utils"
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// 테스트에서 설정 파일과 상태 저장소를 바꿀 수 있도록 변수로 둔다.
+var (
+	notifierConfigPath = "config/notifierConfigs.json"
+	boardTemplatePath  = "config/boardTemplates.json"
+	fetcherConfigPath  = "config/fetcherConfig.json"
+	sinkConfigPath     = "config/sinks.json"
+	openStateStore     = OpenStateStore
+)
+
+// 사용법을 이미 출력한 잘못된 호출
+var errUsage = errors.New("usage")
+
+func printUsage() {
+	fmt.Fprint(os.Stderr, `Usage: notifier [command] [flags]
+
+Commands:
+  run                                    CRAWLING_PERIOD마다 크롤링 (기본값)
+  crawl --once [--topic X]               한 번 크롤링하고 대기 중인 공지를 전송한 뒤 종료
+  dry-run [--topic X]                    새 공지를 JSON으로 출력 (상태 저장, 웹훅 전송 없음)
+  validate-config                        config/ 아래 설정 파일 검사
+  list-topics                            topic 목록 출력
+  state show [--topic X]                 BoxCount/MaxNum과 전송 기록 수 출력
+  state set --topic X [--box N] [--num N]
+                                         BoxCount/MaxNum을 바꾸고 전송 기록을 지움 (다음 크롤링은 이 값으로 판단)
+  state reset --topic X                  전송 기록을 지우고 현재 목록을 기준값으로 다시 기록 (아무것도 전송하지 않음)
+  resend --topic X --id N [--sink S]     목록 페이지의 공지 하나를 topic의 sink(또는 S)로 다시 전송
+`)
+}
+
+// topic이 비어 있으면 모든 topic의 notifier를 만든다. Store를 먼저 열어야 한다.
+func loadNotifiers(topic string) ([]*BaseNotifier, error) {
+	notifierConfigs := LoadNotifierConfig(notifierConfigPath)
+	boardTemplates := LoadBoardTemplates(boardTemplatePath)
+	fetcherConfig := LoadFetcherConfig(fetcherConfigPath)
+	HTTPClient = NewHTTPClient(fetcherConfig.HTTP)
+	PageFetcher = Fetcher{}.New(fetcherConfig, HTTPClient)
+
+	notifiers := make([]*BaseNotifier, 0, len(notifierConfigs))
+	for _, notifierConfig := range notifierConfigs {
+		if topic != "" && notifierConfig.EnglishTopic != topic {
+			continue
+		}
+		boardTemplate, ok := boardTemplates[notifierConfig.Type]
+		if !ok {
+			return nil, fmt.Errorf("board template %d not found for topic %s", notifierConfig.Type, notifierConfig.EnglishTopic)
+		}
+		notifiers = append(notifiers, BaseNotifier{}.New(notifierConfig, boardTemplate))
+	}
+	if topic != "" && len(notifiers) == 0 {
+		return nil, fmt.Errorf("topic %s not found in %s", topic, notifierConfigPath)
+	}
+	return notifiers, nil
+}
+
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+}
+
+func crawlCommand(args []string) error {
+	flags := flag.NewFlagSet("crawl", flag.ContinueOnError)
+	once := flags.Bool("once", false, "한 번만 크롤링하고 종료")
+	topic := flags.String("topic", "", "크롤링할 topic (기본값: 전체)")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if !*once {
+		fmt.Fprintln(os.Stderr, "crawl requires --once; use `run` to crawl periodically")
+		return errUsage
+	}
+
+	Store = openStateStore()
+	defer Store.Close()
+	baseNotifiers, err := loadNotifiers(*topic)
+	if err != nil {
+		return err
+	}
+	sinks, err := NewSinks(LoadSinkConfigs(sinkConfigPath))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	var crawls sync.WaitGroup
+	notifiers := make([]Notifier, 0, len(baseNotifiers))
+	for _, notifier := range baseNotifiers {
+		notifiers = append(notifiers, notifier)
+		crawls.Add(1)
+		go func() {
+			defer crawls.Done()
+			notifier.Notify(ctx)
+		}()
+	}
+	crawls.Wait()
+
+	OutboxWorker{}.New(sinks, notifiers).Deliver(ctx)
+
+	failed := 0
+	for _, notifier := range notifiers {
+		status := notifier.Status()
+		if status.LastError != "" {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: %s\n", status.EnglishTopic, status.LastError)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d topics failed", failed, len(notifiers))
+	}
+	return nil
+}
+
+func dryRunCommand(args []string) error {
+	flags := flag.NewFlagSet("dry-run", flag.ContinueOnError)
+	topic := flags.String("topic", "", "확인할 topic (기본값: 전체)")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	Store = ReadOnlyStore{}.New(openStateStore())
+	defer Store.Close()
+	notifiers, err := loadNotifiers(*topic)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	results := make(map[string][]Notice, len(notifiers))
+	failed := 0
+	for _, notifier := range notifiers {
+		notices, err := notifier.Scrape(ctx)
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: %v\n", notifier.EnglishTopic, err)
+			continue
+		}
+		results[notifier.EnglishTopic] = append(make([]Notice, 0, len(notices)), notices...)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(results)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d topics failed", failed, len(notifiers))
+	}
+	return nil
+}
+
+func validateConfigCommand(args []string) error {
+	flags := flag.NewFlagSet("validate-config", flag.ContinueOnError)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	problems := make([]string, 0)
+	report := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	boardTemplates := LoadBoardTemplates(boardTemplatePath)
+	types := make([]int, 0, len(boardTemplates))
+	for templateType := range boardTemplates {
+		types = append(types, templateType)
+	}
+	sort.Ints(types)
+	for _, templateType := range types {
+		for _, problem := range validateBoardTemplate(boardTemplates[templateType]) {
+			report("%s: type %d: %s", boardTemplatePath, templateType, problem)
+		}
+	}
+
+	sinkNames := make(map[string]bool)
+	for i, sinkConfig := range LoadSinkConfigs(sinkConfigPath) {
+		if sinkNames[sinkConfig.Name] {
+			report("%s: %s: duplicate sink name", sinkConfigPath, sinkConfig.Name)
+		}
+		sinkNames[sinkConfig.Name] = true
+		_, err := NewSink(sinkConfig)
+		if err != nil {
+			report("%s: #%d: %v", sinkConfigPath, i, err)
+		}
+	}
+
+	topics := make(map[string]bool)
+	for i, notifierConfig := range LoadNotifierConfig(notifierConfigPath) {
+		name := notifierConfig.EnglishTopic
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+			report("%s: %s: englishTopic is empty", notifierConfigPath, name)
+		} else if topics[name] {
+			report("%s: %s: duplicate englishTopic", notifierConfigPath, name)
+		}
+		topics[name] = true
+		if notifierConfig.KoreanTopic == "" {
+			report("%s: %s: koreanTopic is empty", notifierConfigPath, name)
+		}
+		if _, ok := boardTemplates[notifierConfig.Type]; !ok {
+			report("%s: %s: no board template for type %d", notifierConfigPath, name, notifierConfig.Type)
+		}
+		noticeUrl, err := url.Parse(notifierConfig.NoticeUrl)
+		if err != nil || (noticeUrl.Scheme != "http" && noticeUrl.Scheme != "https") || noticeUrl.Host == "" {
+			report("%s: %s: invalid noticeUrl %q", notifierConfigPath, name, notifierConfig.NoticeUrl)
+		}
+		for _, sink := range notifierConfig.Sinks {
+			if !sinkNames[sink] {
+				report("%s: %s: sink %s not found in %s", notifierConfigPath, name, sink, sinkConfigPath)
+			}
+		}
+	}
+
+	fetcherConfig := LoadFetcherConfig(fetcherConfigPath)
+	for _, policy := range fetcherConfig.Hosts {
+		if policy.Host == "" || policy.DelayMs < 0 || policy.Burst < 0 {
+			report("%s: invalid host policy %+v", fetcherConfigPath, policy)
+		}
+	}
+
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d problems found", len(problems))
+	}
+	fmt.Printf("ok: %d topics, %d board templates, %d sinks\n", len(topics), len(boardTemplates), len(sinkNames))
+	return nil
+}
+
+// 셀렉터 문법, 인코딩, url 규칙을 검사한다.
+func validateBoardTemplate(template BoardTemplate) []string {
+	problems := make([]string, 0)
+	selectors := map[string]string{
+		"boxNoticeSelector": template.BoxNoticeSelector,
+		"numNoticeSelector": template.NumNoticeSelector,
+		"url.selector":      template.Url.Selector,
+		"contentSelector":   template.ContentSelector,
+		"imagesSelector":    template.ImagesSelector,
+	}
+	for i, selector := range template.RequiredSelectors {
+		selectors[fmt.Sprintf("requiredSelectors[%d]", i)] = selector
+	}
+	for name, rule := range template.Fields {
+		selectors["fields."+name+".selector"] = rule.Selector
+		if rule.IfExists != "" {
+			selectors["fields."+name+".ifExists"] = rule.IfExists
+		}
+	}
+	for name, rule := range template.DetailFields {
+		selectors["detailFields."+name+".selector"] = rule.Selector
+	}
+	// bodySelector가 없으면 HTML, Markdown 본문을 만들지 않는다.
+	if template.BodySelector != "" {
+		selectors["bodySelector"] = template.BodySelector
+	}
+	// attachments는 첨부파일을 보여주는 게시판에만 있다.
+	if attachments := template.Attachments; attachments.Selector != "" {
+		selectors["attachments.selector"] = attachments.Selector
+		for name, selector := range map[string]string{"link": attachments.Link, "name.selector": attachments.Name.Selector, "size.selector": attachments.Size.Selector} {
+			if selector != "" {
+				selectors["attachments."+name] = selector
+			}
+		}
+	}
+
+	names := make([]string, 0, len(selectors))
+	for name := range selectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if selectors[name] == "" {
+			problems = append(problems, name+" is empty")
+			continue
+		}
+		_, err := cascadia.ParseGroup(selectors[name])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+
+	if _, ok := template.Fields["id"]; !ok {
+		problems = append(problems, "fields.id is required")
+	}
+	_, listDate := template.Fields["date"]
+	_, detailDate := template.DetailFields["date"]
+	if (listDate || detailDate) && len(template.DateLayouts) == 0 {
+		problems = append(problems, "dateLayouts is required to read dates")
+	}
+	if template.Encoding != "" {
+		_, err := htmlindex.Get(template.Encoding)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("encoding: %v", err))
+		}
+	}
+	if template.Url.From < 0 || template.Url.To <= template.Url.From {
+		problems = append(problems, fmt.Sprintf("url: invalid range [%d:%d]", template.Url.From, template.Url.To))
+	}
+	if template.Url.Format == "" {
+		problems = append(problems, "url.format is empty")
+	}
+	return problems
+}
+
+func listTopicsCommand(args []string) error {
+	flags := flag.NewFlagSet("list-topics", flag.ContinueOnError)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TOPIC\tTYPE\tKOREAN\tURL")
+	for _, notifierConfig := range LoadNotifierConfig(notifierConfigPath) {
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%s\n", notifierConfig.EnglishTopic, notifierConfig.Type, notifierConfig.KoreanTopic, notifierConfig.NoticeUrl)
+	}
+	return writer.Flush()
+}
+
+func stateCommand(args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return errUsage
+	}
+
+	flags := flag.NewFlagSet("state "+args[0], flag.ContinueOnError)
+	topic := flags.String("topic", "", "topic (englishTopic)")
+	box := flags.Int("box", -1, "저장할 BoxCount")
+	num := flags.Int("num", -1, "저장할 MaxNum")
+	if err := parseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if args[0] != "show" && *topic == "" {
+		fmt.Fprintf(os.Stderr, "state %s requires --topic\n", args[0])
+		return errUsage
+	}
+
+	Store = openStateStore()
+	defer Store.Close()
+
+	switch args[0] {
+	case "show":
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "TOPIC\tBOX\tNUM\tSEEN")
+		for _, notifierConfig := range LoadNotifierConfig(notifierConfigPath) {
+			if *topic != "" && notifierConfig.EnglishTopic != *topic {
+				continue
+			}
+			seen, err := Store.LoadSeenNotices(notifierConfig.EnglishTopic)
+			if err != nil {
+				return err
+			}
+			boxCount, maxNum, err := Store.LoadCursors(notifierConfig.EnglishTopic)
+			if errors.Is(err, ErrTopicNotFound) {
+				fmt.Fprintf(writer, "%s\t-\t-\t%d\n", notifierConfig.EnglishTopic, len(seen))
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(writer, "%s\t%d\t%d\t%d\n", notifierConfig.EnglishTopic, boxCount, maxNum, len(seen))
+		}
+		return writer.Flush()
+
+	case "set":
+		if *box < 0 && *num < 0 {
+			fmt.Fprintln(os.Stderr, "state set requires --box or --num")
+			return errUsage
+		}
+		if *box >= 0 {
+			if err := Store.SaveCursor(*topic, "box", *box); err != nil {
+				return err
+			}
+		}
+		if *num >= 0 {
+			if err := Store.SaveCursor(*topic, "num", *num); err != nil {
+				return err
+			}
+		}
+		// 전송 기록이 남아 있으면 새 공지 판단에 BoxCount/MaxNum을 쓰지 않는다.
+		if err := Store.ClearSeenNotices(*topic); err != nil {
+			return err
+		}
+		Logger.Info("Notice state set by operator", "topic", *topic, "box", *box, "num", *num)
+		return nil
+
+	case "reset":
+		if err := Store.ClearSeenNotices(*topic); err != nil {
+			return err
+		}
+		notifiers, err := loadNotifiers(*topic)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signalContext()
+		defer stop()
+		return notifiers[0].Bootstrap(ctx)
+
+	default:
+		fmt.Fprintf(os.Stderr, "unknown state command %q\n", args[0])
+		return errUsage
+	}
+}
+
+func resendCommand(args []string) error {
+	flags := flag.NewFlagSet("resend", flag.ContinueOnError)
+	topic := flags.String("topic", "", "topic (englishTopic)")
+	id := flags.String("id", "", "목록의 번호 또는 게시글 번호")
+	sinkName := flags.String("sink", "", "보낼 sink (기본값: topic의 sinks)")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *topic == "" || *id == "" {
+		fmt.Fprintln(os.Stderr, "resend requires --topic and --id")
+		return errUsage
+	}
+
+	Store = openStateStore()
+	defer Store.Close()
+	notifiers, err := loadNotifiers(*topic)
+	if err != nil {
+		return err
+	}
+	sinks, err := NewSinks(LoadSinkConfigs(sinkConfigPath))
+	if err != nil {
+		return err
+	}
+	sinkNames := notifiers[0].Sinks
+	if *sinkName != "" {
+		sinkNames = []string{*sinkName}
+	}
+	for _, name := range sinkNames {
+		if _, ok := sinks[name]; !ok {
+			return fmt.Errorf("sink %s not found in %s", name, sinkConfigPath)
+		}
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	notice, err := notifiers[0].Lookup(ctx, *id)
+	if err != nil {
+		return err
+	}
+	for _, name := range sinkNames {
+		err = sinks[name].Send(ctx, notice)
+		if err != nil {
+			return fmt.Errorf("sink %s: %w", name, err)
+		}
+		Logger.Info("Notice resent by operator", "topic", *topic, "sink", name, "notice_id", notice.ID, "url", notice.Url, "title", notice.Title)
+		fmt.Printf("resent %s to %s: %s\n", notice.Url, name, notice.Title)
+	}
+	return nil
+}
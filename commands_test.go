@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "Notifier/models"
+	. "Notifier/src/store"
+	. "Notifier/src/utils"
+)
+
+// 설정된 모든 게시판 템플릿은 검사를 통과하고, 잘못 고친 템플릿은 고친 곳을 알려 준다.
+func TestValidateBoardTemplate(t *testing.T) {
+	templates := LoadBoardTemplates(boardTemplatePath)
+	for templateType, template := range templates {
+		if problems := validateBoardTemplate(template); len(problems) > 0 {
+			t.Errorf("type %d: %v", templateType, problems)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(template *BoardTemplate)
+		want   string
+	}{
+		{"invalid selector", func(template *BoardTemplate) { template.ContentSelector = "div[" }, "contentSelector: "},
+		{"empty selector", func(template *BoardTemplate) { template.NumNoticeSelector = "" }, "numNoticeSelector is empty"},
+		{"invalid field selector", func(template *BoardTemplate) { template.Fields["title"] = FieldRule{Selector: "td:nth-child("} }, "fields.title.selector: "},
+		{"missing id field", func(template *BoardTemplate) { delete(template.Fields, "id") }, "fields.id is required"},
+		{"date without layouts", func(template *BoardTemplate) { template.DateLayouts = nil }, "dateLayouts is required to read dates"},
+		{"unknown encoding", func(template *BoardTemplate) { template.Encoding = "euc-xx" }, "encoding: "},
+		{"invalid url range", func(template *BoardTemplate) { template.Url.From, template.Url.To = 2, 2 }, "url: invalid range [2:2]"},
+		{"empty url format", func(template *BoardTemplate) { template.Url.Format = "" }, "url.format is empty"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			template := templates[1]
+			template.Fields = maps.Clone(template.Fields)
+			test.modify(&template)
+
+			problems := validateBoardTemplate(template)
+			if len(problems) != 1 || !strings.HasPrefix(problems[0], test.want) {
+				t.Errorf("problems = %q, want one starting with %q", problems, test.want)
+			}
+		})
+	}
+}
+
+// ajou-cms fixture를 돌려주는 서버를 Test topic으로 설정하고, 명령이 store를 상태 저장소로 쓰게 한다.
+func useTestCommands(t *testing.T, store StateStore) {
+	t.Helper()
+	fixtures := filepath.Join("src", "notifiers", "testdata", "ajou-cms")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := "list.html"
+		if articleNo := r.URL.Query().Get("articleNo"); articleNo != "" {
+			name = "detail-" + articleNo + ".html"
+		}
+		page, err := os.ReadFile(filepath.Join(fixtures, name))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(page)
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	writeConfig := func(name string, config any) string {
+		t.Helper()
+		data, err := json.Marshal(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		err = os.WriteFile(path, data, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	notifierConfigs := []NotifierConfig{{Type: 1, EnglishTopic: "Test", KoreanTopic: "테스트", NoticeUrl: server.URL + "/kr/ajou/notice.do"}}
+	fetcherConfig := FetcherConfig{DefaultPolicy: HostPolicy{DelayMs: 1, Burst: 100}}
+
+	previousNotifiers, previousFetcher, previousOpen := notifierConfigPath, fetcherConfigPath, openStateStore
+	previousClient, previousPageFetcher, previousStore := HTTPClient, PageFetcher, Store
+	notifierConfigPath = writeConfig("notifierConfigs.json", notifierConfigs)
+	fetcherConfigPath = writeConfig("fetcherConfig.json", fetcherConfig)
+	openStateStore = func() StateStore { return store }
+	t.Cleanup(func() {
+		notifierConfigPath, fetcherConfigPath, openStateStore = previousNotifiers, previousFetcher, previousOpen
+		HTTPClient, PageFetcher, Store = previousClient, previousPageFetcher, previousStore
+	})
+}
+
+func mustSucceed(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStateSetSavesCursorsAndClearsSeenNotices(t *testing.T) {
+	store := MemoryStore{}.New()
+	useTestCommands(t, store)
+	mustSucceed(t, store.SaveSeenNotice("Test", "num", "334470", ""))
+
+	mustSucceed(t, stateCommand([]string{"set", "--topic", "Test", "--box", "1", "--num", "1200"}))
+
+	boxCount, maxNum, err := store.LoadCursors("Test")
+	if err != nil || boxCount != 1 || maxNum != 1200 {
+		t.Errorf("cursors = (%d, %d, %v), want (1, 1200)", boxCount, maxNum, err)
+	}
+	seen, _ := store.LoadSeenNotices("Test")
+	if len(seen) != 0 {
+		t.Errorf("seen notices = %v, want none", seen)
+	}
+
+	// --num만 주면 BoxCount는 그대로 둔다.
+	mustSucceed(t, stateCommand([]string{"set", "--topic", "Test", "--num", "1203"}))
+	boxCount, maxNum, _ = store.LoadCursors("Test")
+	if boxCount != 1 || maxNum != 1203 {
+		t.Errorf("cursors = (%d, %d), want (1, 1203)", boxCount, maxNum)
+	}
+}
+
+func TestStateCommandRejectsMissingFlags(t *testing.T) {
+	store := MemoryStore{}.New()
+	useTestCommands(t, store)
+
+	for _, args := range [][]string{
+		{"set", "--box", "1"},
+		{"set", "--topic", "Test"},
+		{"reset"},
+		{"unknown", "--topic", "Test"},
+	} {
+		if err := stateCommand(args); !errors.Is(err, errUsage) {
+			t.Errorf("state %v = %v, want a usage error", args, err)
+		}
+	}
+	if _, _, err := store.LoadCursors("Test"); !errors.Is(err, ErrTopicNotFound) {
+		t.Errorf("cursors were saved by a rejected command: %v", err)
+	}
+}
+
+// state reset은 지난 전송 기록을 지우고 현재 목록을 기준값으로 기록하며, 아무것도 전송 대기열에 넣지 않는다.
+func TestStateResetRecordsCurrentList(t *testing.T) {
+	store := MemoryStore{}.New()
+	useTestCommands(t, store)
+	mustSucceed(t, store.SaveSeenNotice("Test", "num", "1", ""))
+
+	mustSucceed(t, stateCommand([]string{"reset", "--topic", "Test"}))
+
+	boxCount, maxNum, err := store.LoadCursors("Test")
+	if err != nil || boxCount != 1 || maxNum != 1203 {
+		t.Errorf("cursors = (%d, %d, %v), want (1, 1203)", boxCount, maxNum, err)
+	}
+	seen, _ := store.LoadSeenNotices("Test")
+	for _, key := range []string{"box:334455", "num:334470", "num:334468"} {
+		if _, ok := seen[key]; !ok {
+			t.Errorf("seen notices = %v, want %s recorded", seen, key)
+		}
+	}
+	if _, ok := seen["num:1"]; ok {
+		t.Errorf("seen notices = %v, want the old record cleared", seen)
+	}
+	due, _ := store.LoadDueOutboxEntries(10)
+	if len(due) != 0 {
+		t.Errorf("due entries = %+v, want none", due)
+	}
+}
+
+// dry-run은 새 공지를 출력만 하고 상태 저장소에는 아무것도 쓰지 않는다.
+func TestDryRunDoesNotWriteState(t *testing.T) {
+	store := MemoryStore{}.New()
+	useTestCommands(t, store)
+	mustSucceed(t, store.SaveCursor("Test", "box", 1))
+	mustSucceed(t, store.SaveCursor("Test", "num", 1201))
+	mustSucceed(t, store.SaveSeenNotice("Test", "box", "334455", ""))
+
+	output, err := os.CreateTemp(t.TempDir(), "stdout")
+	mustSucceed(t, err)
+	stdout := os.Stdout
+	os.Stdout = output
+	err = dryRunCommand([]string{"--topic", "Test"})
+	os.Stdout = stdout
+	mustSucceed(t, err)
+
+	data, err := os.ReadFile(output.Name())
+	mustSucceed(t, err)
+	var results map[string][]Notice
+	mustSucceed(t, json.Unmarshal(data, &results))
+	if len(results["Test"]) != 2 {
+		t.Fatalf("dry-run output = %s, want notices 1203 and 1202", data)
+	}
+
+	boxCount, maxNum, _ := store.LoadCursors("Test")
+	if boxCount != 1 || maxNum != 1201 {
+		t.Errorf("cursors = (%d, %d), want (1, 1201) unchanged", boxCount, maxNum)
+	}
+	seen, _ := store.LoadSeenNotices("Test")
+	if len(seen) != 1 {
+		t.Errorf("seen notices = %v, want only box:334455", seen)
+	}
+	due, _ := store.LoadDueOutboxEntries(10)
+	if len(due) != 0 {
+		t.Errorf("due entries = %+v, want none", due)
+	}
+}
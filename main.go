@@ -1,67 +1,244 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	. "Notifier/src/notifiers"
+	. "Notifier/src/server"
+	. "Notifier/src/sinks"
+	. "Notifier/src/store"
 	. "Notifier/src/utils"
 )
 
 func main() {
+	command := "run"
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
 	CreateDir("logs")
 
-	errorLogFile := OpenLogFile("logs/errorLog.txt")
-	defer errorLogFile.Close()
-	ErrorLogger = CreateLogger(errorLogFile)
-
-	sentNoticeLogFile := OpenLogFile("logs/sentNoticeLog.txt")
-	defer sentNoticeLogFile.Close()
-	SentNoticeLogger = CreateLogger(sentNoticeLogFile)
-
-	postLogFile := OpenLogFile("logs/postLog.txt")
-	defer postLogFile.Close()
-	PostLogger = CreateLogger(postLogFile)
-
-	DB = ConnectDB()
-
-	notifierConfigs := LoadNotifierConfig("config/notifierConfigs.json")
-
-	notifiers := make([]Notifier, 0, len(notifierConfigs))
-	for _, notifierConfig := range notifierConfigs {
-		baseNotifier := BaseNotifier{}.New(notifierConfig)
-		var notifier Notifier
-		switch notifierConfig.Type {
-		case 1:
-			notifier = Type1Notifier{}.New(baseNotifier)
-		case 2:
-			notifier = Type2Notifier{}.New(baseNotifier)
-		case 3:
-			notifier = Type3Notifier{}.New(baseNotifier)
-		case 4:
-			notifier = Type4Notifier{}.New(baseNotifier)
-		case 5:
-			notifier = Type5Notifier{}.New(baseNotifier)
+	logCloser := InitLogger(LoadLogConfig())
+	defer logCloser.Close()
+
+	WebhookSecret = []byte(os.Getenv("WEBHOOK_SECRET"))
+	defer RedisClient.Close()
+
+	var err error
+	switch command {
+	case "run":
+		run()
+	case "crawl":
+		err = crawlCommand(args)
+	case "dry-run":
+		err = dryRunCommand(args)
+	case "validate-config":
+		err = validateConfigCommand(args)
+	case "list-topics":
+		err = listTopicsCommand(args)
+	case "state":
+		err = stateCommand(args)
+	case "resend":
+		err = resendCommand(args)
+	case "help":
+		printUsage()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", command)
+		printUsage()
+		err = errUsage
+	}
+
+	if err != nil {
+		if !errors.Is(err, errUsage) && !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "error:", err)
 		}
+		logCloser.Close()
+		os.Exit(1)
+	}
+}
+
+// 종료 신호를 받을 때까지 CRAWLING_PERIOD마다 크롤링한다.
+func run() {
+	Store = OpenStateStore()
+	defer Store.Close()
+
+	baseNotifiers, err := loadNotifiers("")
+	if err != nil {
+		LogPanic("Failed to load notifiers", err)
+	}
+	notifiers := make([]Notifier, 0, len(baseNotifiers))
+	for _, notifier := range baseNotifiers {
 		notifiers = append(notifiers, notifier)
 	}
 
+	sinks, err := NewSinks(LoadSinkConfigs(sinkConfigPath))
+	if err != nil {
+		LogPanic("Failed to load sinks", err)
+	}
+
 	crawlingPeriod, err := strconv.Atoi(os.Getenv("CRAWLING_PERIOD"))
 	if err != nil {
-		ErrorLogger.Panic(err)
+		LogPanic("Invalid CRAWLING_PERIOD", err)
+	}
+
+	drainTimeout := 30 * time.Second
+	if value := os.Getenv("DRAIN_TIMEOUT"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			LogPanic("Invalid DRAIN_TIMEOUT", err)
+		}
+		drainTimeout = time.Duration(seconds) * time.Second
+	}
+
+	recheck := loadRecheckConfig()
+
+	// SIGINT/SIGTERM을 받으면 ctx가 취소된다.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// delay만큼 기다린 뒤 실행한다. 기다리는 중에 종료 신호를 받으면 실행하지 않는다.
+	var crawls sync.WaitGroup
+	runNotifier := func(notifier Notifier, delay time.Duration) {
+		crawls.Add(1)
+		go func() {
+			defer crawls.Done()
+			if delay > 0 {
+				timer := time.NewTimer(delay)
+				defer timer.Stop()
+				select {
+				case <-ctx.Done():
+					return
+				case <-timer.C:
+				}
+			}
+			notifier.Notify(ctx)
+		}()
+	}
+	recheckNotifier := func(notifier Notifier) {
+		crawls.Add(1)
+		go func() {
+			defer crawls.Done()
+			notifier.Recheck(ctx, time.Now().Add(-recheck.Window), recheck.Limit)
+		}()
+	}
+
+	outboxWorker := OutboxWorker{}.New(sinks, notifiers)
+	outboxDone := make(chan struct{})
+	go func() {
+		defer close(outboxDone)
+		outboxWorker.Run(ctx, 10*time.Second)
+	}()
+
+	adminPort := os.Getenv("ADMIN_PORT")
+	if adminPort == "" {
+		adminPort = "1323"
 	}
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	if adminToken == "" {
+		Logger.Warn("ADMIN_TOKEN is not set, admin control routes are disabled")
+	}
+	adminServer := AdminServer{}.New(notifiers, []byte(adminToken), func(notifier Notifier) {
+		runNotifier(notifier, 0)
+	})
+	go func() {
+		err := adminServer.ListenAndServe(":" + adminPort)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			LogPanic("Admin server stopped", err)
+		}
+	}()
+
 	noticeTicker := time.NewTicker(time.Duration(crawlingPeriod) * time.Second)
 	defer noticeTicker.Stop()
 
+	// RECHECK_PERIOD가 0이면 채널이 nil이라 수정 확인을 하지 않는다.
+	var recheckTicks <-chan time.Time
+	if recheck.Period > 0 {
+		recheckTicker := time.NewTicker(recheck.Period)
+		defer recheckTicker.Stop()
+		recheckTicks = recheckTicker.C
+	}
+
 	for {
 		select {
+		case <-ctx.Done():
+			shutdown(adminServer, &crawls, outboxDone, drainTimeout)
+			return
 		case <-noticeTicker.C:
-			log.Print("working")
+			Logger.Info("Crawl tick", "notifiers", len(notifiers))
+			// 모든 notifier가 같은 순간에 요청하지 않도록 시작 시각을 흩뜨린다.
 			for _, notifier := range notifiers {
-				go notifier.Notify()
+				runNotifier(notifier, PageFetcher.StartDelay())
 			}
+		case <-recheckTicks:
+			Logger.Info("Recheck tick", "notifiers", len(notifiers), "window", recheck.Window.String(), "limit", recheck.Limit)
+			for _, notifier := range notifiers {
+				recheckNotifier(notifier)
+			}
+		}
+	}
+}
+
+// 이미 보낸 공지의 수정 여부를 확인하는 주기와 범위
+type recheckConfig struct {
+	Period time.Duration // RECHECK_PERIOD초, 0이면 확인하지 않는다.
+	Window time.Duration // RECHECK_DAYS일 안에 보낸 공지만 확인한다.
+	Limit  int           // RECHECK_LIMIT, topic마다 최근 공지 몇 개까지 확인할지
+}
+
+func loadRecheckConfig() recheckConfig {
+	envInt := func(key string, fallback int) int {
+		value := os.Getenv(key)
+		if value == "" {
+			return fallback
 		}
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 0 {
+			LogPanic("Invalid "+key, fmt.Errorf("%q is not a non-negative integer", value))
+		}
+		return number
+	}
+	return recheckConfig{
+		Period: time.Duration(envInt("RECHECK_PERIOD", 3600)) * time.Second,
+		Window: time.Duration(envInt("RECHECK_DAYS", 14)) * 24 * time.Hour,
+		Limit:  envInt("RECHECK_LIMIT", 20),
+	}
+}
+
+// 진행 중인 크롤링과 웹훅 전송이 끝나기를 drainTimeout까지 기다린다.
+func shutdown(adminServer *AdminServer, crawls *sync.WaitGroup, outboxDone <-chan struct{}, drainTimeout time.Duration) {
+	Logger.Info("Shutting down", "drain_timeout", drainTimeout.String())
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	err := adminServer.Shutdown(drainCtx)
+	if err != nil {
+		Logger.Error("Failed to shut down admin server", "error", err)
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		crawls.Wait()
+		<-outboxDone
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		Logger.Info("Shutdown complete")
+	case <-drainCtx.Done():
+		Logger.Warn("Drain timeout exceeded, exiting with work in flight")
 	}
 }
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// notice_archive 테이블의 한 행. 한 sink에라도 전송된 공지를 피드용으로 보관한다.
+type ArchivedNotice struct {
+	Topic       string
+	ArticleId   string
+	DeliveredAt time.Time
+	Notice      Notice
+}
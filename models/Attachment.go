@@ -0,0 +1,9 @@
+package models
+
+// 상세 페이지의 첨부파일 하나
+type Attachment struct {
+	Name      string `json:"name"`
+	Url       string `json:"url"`                 // 절대 주소
+	Size      string `json:"size,omitempty"`      // 게시판에 보이는 그대로 (예: 245KB)
+	Extension string `json:"extension,omitempty"` // 점 없는 소문자 (예: pdf)
+}
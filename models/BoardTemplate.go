@@ -0,0 +1,53 @@
+package models
+
+// 게시판 레이아웃 하나를 선언적으로 기술한다. NotifierConfig.Type 값으로 참조된다.
+// Fields는 목록 행에서, DetailFields는 상세 페이지에서 읽는다. 둘 다 있으면 상세 페이지 값을 쓴다.
+// date, modifiedDate 값은 DateLayouts의 형식을 차례로 시도해 서울 시각으로 읽는다.
+type BoardTemplate struct {
+	Type              int                  `json:"type"`
+	Name              string               `json:"name"`
+	BoxNoticeSelector string               `json:"boxNoticeSelector"`
+	NumNoticeSelector string               `json:"numNoticeSelector"`
+	RequiredSelectors []string             `json:"requiredSelectors"`
+	Fields            map[string]FieldRule `json:"fields"`
+	DetailFields      map[string]FieldRule `json:"detailFields"`
+	DateLayouts       []string             `json:"dateLayouts"`
+	Url               UrlRule              `json:"url"`
+	ContentSelector   string               `json:"contentSelector"`
+	BodySelector      string               `json:"bodySelector"` // 본문 전체를 감싼 요소. HTML, Markdown 본문을 만든다.
+	ImagesSelector    string               `json:"imagesSelector"`
+	ImageHost         string               `json:"imageHost"`
+	Attachments       AttachmentRule       `json:"attachments"`
+	Encoding          string               `json:"encoding"`
+	ArticleIdParam    string               `json:"articleIdParam"`
+}
+
+// 목록의 한 행에서 Notice 필드 하나를 읽어오는 규칙
+type FieldRule struct {
+	Selector   string `json:"selector"`
+	Attr       string `json:"attr"`
+	TrimSuffix string `json:"trimSuffix"`
+	IfExists   string `json:"ifExists"`
+	Value      string `json:"value"`
+}
+
+// 목록의 링크를 상세 페이지 URL로 바꾸는 규칙
+// Format의 {noticeUrl}은 TrimNoticeUrlSuffix를 제거한 NoticeUrl, {parts}는 Separator로 나눈 링크의 [From:To] 구간이다.
+type UrlRule struct {
+	Selector            string `json:"selector"`
+	Attr                string `json:"attr"`
+	Separator           string `json:"separator"`
+	From                int    `json:"from"`
+	To                  int    `json:"to"`
+	Format              string `json:"format"`
+	TrimNoticeUrlSuffix string `json:"trimNoticeUrlSuffix"`
+}
+
+// 상세 페이지의 첨부파일을 읽는 규칙. Selector는 파일마다 하나씩 맞는 요소이고 나머지는 그 안에서 찾는다.
+// Link가 비어 있으면 Selector가 링크다. Name 규칙이 없으면 링크 텍스트를 파일 이름으로 쓴다.
+type AttachmentRule struct {
+	Selector string    `json:"selector"`
+	Link     string    `json:"link"`
+	Name     FieldRule `json:"name"`
+	Size     FieldRule `json:"size"`
+}
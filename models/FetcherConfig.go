@@ -0,0 +1,29 @@
+package models
+
+// 대학 서버에 보내는 요청의 속도 제한 설정
+type FetcherConfig struct {
+	MaxInFlight   int          `json:"maxInFlight"`
+	DefaultPolicy HostPolicy   `json:"defaultPolicy"`
+	Hosts         []HostPolicy `json:"hosts"`
+	StartJitterMs int          `json:"startJitterMs"`
+	HTTP          HTTPConfig   `json:"http"`
+}
+
+// 페이지 요청과 웹훅이 함께 쓰는 HTTP 클라이언트 설정
+type HTTPConfig struct {
+	UserAgent           string `json:"userAgent"`
+	ConnectTimeoutMs    int    `json:"connectTimeoutMs"`
+	ReadTimeoutMs       int    `json:"readTimeoutMs"`
+	RequestTimeoutMs    int    `json:"requestTimeoutMs"`
+	MaxIdleConnsPerHost int    `json:"maxIdleConnsPerHost"`
+	MaxRetries          int    `json:"maxRetries"`
+	RetryBaseDelayMs    int    `json:"retryBaseDelayMs"`
+}
+
+// 호스트별 토큰 버킷 설정. DelayMs마다 토큰이 하나씩 채워지고 최대 Burst개까지 쌓인다.
+// Host는 정확히 일치하는 호스트나 그 하위 도메인에 적용되며, 하위 도메인들은 버킷 하나를 함께 쓴다.
+type HostPolicy struct {
+	Host    string `json:"host"`
+	DelayMs int    `json:"delayMs"`
+	Burst   int    `json:"burst"`
+}
@@ -1,14 +1,20 @@
 package models
 
 type Notice struct {
-	ID           string   `json:"id"`
-	Category     string   `json:"category"`
-	Title        string   `json:"title"`
-	Department   string   `json:"department"`
-	Date         string   `json:"date"`
-	Url          string   `json:"url"`
-	Content      string   `json:"content"`
-	Images       []string `json:"images"`
-	EnglishTopic string   `json:"englishTopic"`
-	KoreanTopic  string   `json:"koreanTopic"`
+	ID           string        `json:"id"`
+	Category     string        `json:"category"`
+	Title        string        `json:"title"`
+	Department   string        `json:"department"`
+	Date         string        `json:"date"`                   // 게시일 (RFC3339, +09:00). 읽지 못하면 CrawledAt과 같다.
+	ModifiedDate string        `json:"modifiedDate,omitempty"` // 게시판에 수정일이 있을 때만 채운다.
+	CrawledAt    string        `json:"crawledAt"`              // 상세 페이지를 읽은 시각 (RFC3339, +09:00)
+	Url          string        `json:"url"`
+	Content      string        `json:"content"`         // 문단을 "\n"으로 이은 평문
+	ContentHTML  string        `json:"contentHtml"`     // 허용한 태그만 남긴 본문 HTML
+	ContentMD    string        `json:"contentMarkdown"` // 같은 본문의 Markdown
+	Images       []string      `json:"images"`
+	Attachments  []Attachment  `json:"attachments"`
+	Tables       []NoticeTable `json:"tables"`
+	EnglishTopic string        `json:"englishTopic"`
+	KoreanTopic  string        `json:"koreanTopic"`
 }
@@ -0,0 +1,15 @@
+package models
+
+// 이미 보낸 공지에 생긴 변화. Event는 updated 등이다.
+type NoticeEvent struct {
+	Event   string        `json:"event"`
+	Notice  Notice        `json:"notice"` // 바뀐 뒤의 공지
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// 필드 하나의 변경 전후 값. Field는 title, content, images 중 하나다.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// 본문의 표. rowspan, colspan으로 합친 칸은 차지하는 자리마다 같은 값을 넣어 모든 행의 길이를 맞춘다.
+type NoticeTable struct {
+	Caption string     `json:"caption,omitempty"`
+	Header  []string   `json:"header"` // 머리글이 여러 줄이면 열마다 이어 붙인다. 머리글이 없으면 첫 행을 쓴다.
+	Rows    [][]string `json:"rows"`
+}
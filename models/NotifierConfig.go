@@ -1,8 +1,9 @@
 package models
 
 type NotifierConfig struct {
-	Type         int    `json:"type"`
-	EnglishTopic string `json:"englishTopic"`
-	KoreanTopic  string `json:"koreanTopic"`
-	NoticeUrl    string `json:"noticeUrl"`
+	Type         int      `json:"type"`
+	EnglishTopic string   `json:"englishTopic"`
+	KoreanTopic  string   `json:"koreanTopic"`
+	NoticeUrl    string   `json:"noticeUrl"`
+	Sinks        []string `json:"sinks"` // config/sinks.json의 name. 비어 있으면 backend로만 보낸다.
 }
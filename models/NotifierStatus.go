@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// 관리 서버에서 보여주는 notifier의 현재 상태
+type NotifierStatus struct {
+	Type          int        `json:"type"`
+	EnglishTopic  string     `json:"englishTopic"`
+	KoreanTopic   string     `json:"koreanTopic"`
+	NoticeUrl     string     `json:"noticeUrl"`
+	Paused        bool       `json:"paused"`
+	Running       bool       `json:"running"`
+	SkippedRuns   int        `json:"skippedRuns"`
+	LastRunId     string     `json:"lastRunId"`
+	LastRunAt     *time.Time `json:"lastRunAt"`
+	LastError     string     `json:"lastError"`
+	BoxCount      int        `json:"boxCount"`
+	MaxNum        int        `json:"maxNum"`
+	LastDelivered *Notice    `json:"lastDelivered"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// notice_outbox 테이블의 한 행. 전송이 확인될 때까지 Notice를 보관한다.
+// 공지 하나는 보낼 곳(Sink)마다 한 행씩 들어간다.
+// Kind가 box, num이면 새 공지이고, 그 밖의 값이면 이미 보낸 공지의 이벤트(NoticeEvent.Event)다.
+type OutboxEntry struct {
+	ID          int64
+	Topic       string
+	Kind        string
+	ArticleId   string
+	Sink        string
+	Fingerprint string // box, num이면 목록 행의 지문, 이벤트면 같은 이벤트를 두 번 넣지 않기 위한 지문이다.
+	Cursor      int
+	Attempts    int
+	Notice      Notice
+	Changes     []FieldChange // 이벤트일 때만 채운다.
+}
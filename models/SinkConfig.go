@@ -0,0 +1,23 @@
+package models
+
+// 공지를 보낼 곳 하나. 문자열 값의 ${ENV}는 환경변수로 바뀐다.
+// Type은 backend, slack, discord, mattermost, email, file, stdout, redis-stream 중 하나다.
+type SinkConfig struct {
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	Url      string     `json:"url,omitempty"`      // backend, slack, discord, mattermost, redis-stream(비어 있으면 REDIS_HOST)
+	EventUrl string     `json:"eventUrl,omitempty"` // backend. 비어 있으면 이벤트를 보내지 않는다.
+	Path     string     `json:"path,omitempty"`     // file
+	SMTP     SMTPConfig `json:"smtp,omitempty"`     // email
+	Stream   string     `json:"stream,omitempty"`   // redis-stream. {topic}은 englishTopic으로 바뀐다.
+	MaxLen   int64      `json:"maxLen,omitempty"`   // redis-stream. 스트림에 남길 대략적인 최대 길이
+}
+
+type SMTPConfig struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
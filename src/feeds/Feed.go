@@ -0,0 +1,315 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"html"
+	"strings"
+	"time"
+
+	. "Notifier/models"
+)
+
+const (
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+	AtomContentType = "application/atom+xml; charset=utf-8"
+	JSONContentType = "application/feed+json; charset=utf-8"
+)
+
+// 보관된 공지로 만드는 피드 하나. Items는 최근에 전송한 것부터 정렬되어 있다.
+type Feed struct {
+	Title       string
+	Link        string // 공지 목록 페이지
+	SelfUrl     string // 이 피드의 주소
+	Description string
+	Items       []ArchivedNotice
+	PrefixTopic bool // 여러 topic을 모은 피드이면 제목 앞에 topic을 붙인다.
+}
+
+// 가장 최근 항목의 전송 시각. 항목이 없으면 now를 쓴다.
+func (feed Feed) updated(now time.Time) time.Time {
+	if len(feed.Items) == 0 {
+		return now
+	}
+	return feed.Items[0].DeliveredAt
+}
+
+func (feed Feed) itemTitle(notice Notice) string {
+	title := notice.Title
+	if notice.Category != "" {
+		title = "[" + notice.Category + "] " + title
+	}
+	if feed.PrefixTopic {
+		title = "[" + notice.KoreanTopic + "] " + title
+	}
+	return title
+}
+
+// 게시일을 읽을 수 없으면 처음 전송한 시각을 쓴다.
+func publishedAt(item ArchivedNotice) time.Time {
+	published, err := time.Parse(time.RFC3339, item.Notice.Date)
+	if err != nil {
+		return item.DeliveredAt
+	}
+	return published
+}
+
+// 수정일이 있으면 수정일, 없으면 게시일
+func updatedAt(item ArchivedNotice) time.Time {
+	modified, err := time.Parse(time.RFC3339, item.Notice.ModifiedDate)
+	if err != nil {
+		return publishedAt(item)
+	}
+	return modified
+}
+
+// Content의 줄바꿈은 "\n" 문자열로 저장되어 있다.
+func contentText(notice Notice) string {
+	return strings.ReplaceAll(notice.Content, "\\n", "\n")
+}
+
+// 본문, 이미지, 첨부파일 링크를 HTML로 만든다. 정리한 본문 HTML이 있으면 그대로 쓰고 본문에 없는 이미지만 덧붙인다.
+// 없으면 평문 본문을 모두 이스케이프해 문단으로 나눈다.
+func contentHTML(notice Notice) string {
+	var builder strings.Builder
+	builder.WriteString(notice.ContentHTML)
+	if notice.ContentHTML == "" {
+		for _, line := range strings.Split(contentText(notice), "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			builder.WriteString("<p>" + html.EscapeString(line) + "</p>")
+		}
+	}
+	for _, image := range notice.Images {
+		src := html.EscapeString(image)
+		if strings.Contains(notice.ContentHTML, `src="`+src+`"`) {
+			continue
+		}
+		builder.WriteString(`<p><img src="` + src + `" alt=""></p>`)
+	}
+	if len(notice.Attachments) > 0 {
+		builder.WriteString("<ul>")
+		for _, attachment := range notice.Attachments {
+			builder.WriteString(`<li><a href="` + html.EscapeString(attachment.Url) + `">` + html.EscapeString(attachment.Name) + "</a></li>")
+		}
+		builder.WriteString("</ul>")
+	}
+	return builder.String()
+}
+
+type rssDocument struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	AtomSpace string     `xml:"xmlns:atom,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string      `xml:"title"`
+	Link          string      `xml:"link"`
+	Description   string      `xml:"description"`
+	Language      string      `xml:"language"`
+	LastBuildDate string      `xml:"lastBuildDate"`
+	SelfLink      rssSelfLink `xml:"atom:link"`
+	Items         []rssItem   `xml:"item"`
+}
+
+type rssSelfLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Guid        rssGuid `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Category    string  `xml:"category,omitempty"`
+	Description string  `xml:"description"`
+}
+
+type rssGuid struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS 2.0 문서를 만든다.
+func (feed Feed) RSS(now time.Time) ([]byte, error) {
+	document := rssDocument{
+		Version:   "2.0",
+		AtomSpace: "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Description:   feed.Description,
+			Language:      "ko",
+			LastBuildDate: feed.updated(now).Format(time.RFC1123Z),
+			SelfLink:      rssSelfLink{Href: feed.SelfUrl, Rel: "self", Type: "application/rss+xml"},
+			Items:         make([]rssItem, 0, len(feed.Items)),
+		},
+	}
+	for _, item := range feed.Items {
+		document.Channel.Items = append(document.Channel.Items, rssItem{
+			Title:       feed.itemTitle(item.Notice),
+			Link:        item.Notice.Url,
+			Guid:        rssGuid{IsPermaLink: true, Value: item.Notice.Url},
+			PubDate:     publishedAt(item).Format(time.RFC1123Z),
+			Category:    item.Notice.Category,
+			Description: contentHTML(item.Notice),
+		})
+	}
+	return marshalXML(document)
+}
+
+type atomDocument struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string        `xml:"title"`
+	ID        string        `xml:"id"`
+	Updated   string        `xml:"updated"`
+	Published string        `xml:"published"`
+	Link      atomLink      `xml:"link"`
+	Author    *atomAuthor   `xml:"author,omitempty"`
+	Category  *atomCategory `xml:"category,omitempty"`
+	Content   atomContent   `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Atom 1.0 문서를 만든다.
+func (feed Feed) Atom(now time.Time) ([]byte, error) {
+	document := atomDocument{
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		ID:       feed.SelfUrl,
+		Updated:  feed.updated(now).Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: feed.SelfUrl, Rel: "self", Type: "application/atom+xml"},
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+		},
+		Entries: make([]atomEntry, 0, len(feed.Items)),
+	}
+	for _, item := range feed.Items {
+		entry := atomEntry{
+			Title:     feed.itemTitle(item.Notice),
+			ID:        item.Notice.Url,
+			Updated:   updatedAt(item).Format(time.RFC3339),
+			Published: publishedAt(item).Format(time.RFC3339),
+			Link:      atomLink{Href: item.Notice.Url, Rel: "alternate", Type: "text/html"},
+			Content:   atomContent{Type: "html", Value: contentHTML(item.Notice)},
+		}
+		if item.Notice.Department != "" {
+			entry.Author = &atomAuthor{Name: item.Notice.Department}
+		}
+		if item.Notice.Category != "" {
+			entry.Category = &atomCategory{Term: item.Notice.Category}
+		}
+		document.Entries = append(document.Entries, entry)
+	}
+	return marshalXML(document)
+}
+
+func marshalXML(document any) ([]byte, error) {
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+type jsonFeedDocument struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageUrl string         `json:"home_page_url"`
+	FeedUrl     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Language    string         `json:"language"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	Url           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html"`
+	ContentText   string           `json:"content_text"`
+	Image         string           `json:"image,omitempty"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// JSON Feed 1.1 문서를 만든다.
+func (feed Feed) JSON() ([]byte, error) {
+	document := jsonFeedDocument{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageUrl: feed.Link,
+		FeedUrl:     feed.SelfUrl,
+		Description: feed.Description,
+		Language:    "ko",
+		Items:       make([]jsonFeedItem, 0, len(feed.Items)),
+	}
+	for _, item := range feed.Items {
+		jsonItem := jsonFeedItem{
+			ID:            item.Notice.Url,
+			Url:           item.Notice.Url,
+			Title:         feed.itemTitle(item.Notice),
+			ContentHTML:   contentHTML(item.Notice),
+			ContentText:   contentText(item.Notice),
+			DatePublished: publishedAt(item).Format(time.RFC3339),
+		}
+		if item.Notice.ModifiedDate != "" {
+			jsonItem.DateModified = updatedAt(item).Format(time.RFC3339)
+		}
+		if len(item.Notice.Images) > 0 {
+			jsonItem.Image = item.Notice.Images[0]
+		}
+		if item.Notice.Category != "" {
+			jsonItem.Tags = []string{item.Notice.Category}
+		}
+		if item.Notice.Department != "" {
+			jsonItem.Authors = []jsonFeedAuthor{{Name: item.Notice.Department}}
+		}
+		document.Items = append(document.Items, jsonItem)
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(document)
+	return buf.Bytes(), err
+}
@@ -0,0 +1,155 @@
+package feeds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	. "Notifier/models"
+)
+
+var deliveredAt = time.Date(2024, 9, 2, 9, 30, 0, 0, time.FixedZone("KST", 9*60*60))
+
+var testFeed = Feed{
+	Title:       "아주대학교-일반",
+	Link:        "https://ajou.ac.kr/kr/ajou/notice.do",
+	SelfUrl:     "http://localhost:1323/feeds/AjouNormal.xml",
+	Description: "아주대학교-일반 공지사항",
+	Items: []ArchivedNotice{{
+		Topic:       "AjouNormal",
+		ArticleId:   "1203",
+		DeliveredAt: deliveredAt,
+		Notice: Notice{
+			ID:          "1203",
+			Category:    "학사",
+			Title:       "수강신청 <변경> 안내",
+			Department:  "학사팀",
+			Url:         "https://ajou.ac.kr/kr/ajou/notice.do?mode=view&articleNo=1203",
+			Content:     "신청 기간: 9월 2일~9월 6일\\n문의: 학사팀",
+			Images:      []string{"https://ajou.ac.kr/upload/schedule.png"},
+			Attachments: []Attachment{{Name: "변경 신청서.hwp", Url: "https://ajou.ac.kr/kr/ajou/notice.do?mode=download&articleNo=1203&attachNo=1"}},
+			KoreanTopic: "아주대학교-일반",
+		},
+	}},
+}
+
+func TestRSS(t *testing.T) {
+	body, err := testFeed.RSS(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document rssDocument
+	err = xml.Unmarshal(body, &document)
+	if err != nil {
+		t.Fatalf("invalid RSS: %v\n%s", err, body)
+	}
+	if len(document.Channel.Items) != 1 {
+		t.Fatalf("items = %+v", document.Channel.Items)
+	}
+	item := document.Channel.Items[0]
+	if item.Title != "[학사] 수강신청 <변경> 안내" || item.Link != testFeed.Items[0].Notice.Url || item.PubDate != "Mon, 02 Sep 2024 09:30:00 +0900" {
+		t.Errorf("item = %+v", item)
+	}
+	for _, want := range []string{"<p>신청 기간: 9월 2일~9월 6일</p>", "<p>문의: 학사팀</p>", `<img src="https://ajou.ac.kr/upload/schedule.png" alt="">`,
+		`<li><a href="https://ajou.ac.kr/kr/ajou/notice.do?mode=download&amp;articleNo=1203&amp;attachNo=1">변경 신청서.hwp</a></li>`} {
+		if !strings.Contains(item.Description, want) {
+			t.Errorf("description missing %q: %s", want, item.Description)
+		}
+	}
+}
+
+// 정리한 본문 HTML이 있으면 평문 본문 대신 쓰고, 본문에 이미 있는 이미지는 다시 붙이지 않는다.
+func TestContentHTMLUsesSanitizedBody(t *testing.T) {
+	notice := testFeed.Items[0].Notice
+	notice.ContentHTML = `<p>신청 <strong>기간</strong>: 9월 2일~9월 6일</p><p><img src="https://ajou.ac.kr/upload/schedule.png" alt=""/></p>`
+	notice.Images = append(notice.Images, "https://ajou.ac.kr/upload/poster.png")
+
+	got := contentHTML(notice)
+	if !strings.HasPrefix(got, notice.ContentHTML) || strings.Contains(got, "<p>문의: 학사팀</p>") {
+		t.Errorf("content = %s, want the sanitized body instead of the plain text", got)
+	}
+	if strings.Count(got, "schedule.png") != 1 || !strings.Contains(got, `<img src="https://ajou.ac.kr/upload/poster.png" alt="">`) {
+		t.Errorf("content = %s, want each image once", got)
+	}
+}
+
+func TestAtom(t *testing.T) {
+	body, err := testFeed.Atom(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), `<feed xmlns="http://www.w3.org/2005/Atom">`) {
+		t.Errorf("missing Atom namespace:\n%s", body)
+	}
+	var document atomDocument
+	err = xml.Unmarshal(body, &document)
+	if err != nil {
+		t.Fatalf("invalid Atom: %v\n%s", err, body)
+	}
+	if document.Updated != "2024-09-02T09:30:00+09:00" || len(document.Entries) != 1 {
+		t.Fatalf("feed = %+v", document)
+	}
+	entry := document.Entries[0]
+	if entry.ID != testFeed.Items[0].Notice.Url || entry.Author == nil || entry.Author.Name != "학사팀" || entry.Content.Type != "html" {
+		t.Errorf("entry = %+v", entry)
+	}
+
+	// 게시일과 수정일이 있으면 전송 시각 대신 쓴다.
+	feed := testFeed
+	feed.Items = []ArchivedNotice{feed.Items[0]}
+	feed.Items[0].Notice.Date = "2024-08-30T14:00:00+09:00"
+	feed.Items[0].Notice.ModifiedDate = "2024-09-01T10:00:00+09:00"
+	body, err = feed.Atom(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dated atomDocument
+	err = xml.Unmarshal(body, &dated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry = dated.Entries[0]
+	if entry.Published != "2024-08-30T14:00:00+09:00" || entry.Updated != "2024-09-01T10:00:00+09:00" {
+		t.Errorf("entry dates = (%s, %s), want the posted and modified dates", entry.Published, entry.Updated)
+	}
+}
+
+func TestJSONFeed(t *testing.T) {
+	feed := testFeed
+	feed.PrefixTopic = true
+	body, err := feed.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "mode=view&articleNo=1203") {
+		t.Errorf("url was escaped:\n%s", body)
+	}
+	var document jsonFeedDocument
+	err = json.Unmarshal(body, &document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document.Version != "https://jsonfeed.org/version/1.1" || len(document.Items) != 1 {
+		t.Fatalf("feed = %+v", document)
+	}
+	item := document.Items[0]
+	if item.Title != "[아주대학교-일반] [학사] 수강신청 <변경> 안내" {
+		t.Errorf("title = %q", item.Title)
+	}
+	if item.ContentText != "신청 기간: 9월 2일~9월 6일\n문의: 학사팀" || item.Image != "https://ajou.ac.kr/upload/schedule.png" || item.DatePublished != "2024-09-02T09:30:00+09:00" {
+		t.Errorf("item = %+v", item)
+	}
+}
+
+func TestEmptyFeedIsValid(t *testing.T) {
+	feed := Feed{Title: "빈 피드", Link: "https://ajou.ac.kr", SelfUrl: "http://localhost/feeds/Empty.json"}
+	body, err := feed.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), `"items": []`) {
+		t.Errorf("empty feed must have an items array:\n%s", body)
+	}
+}
@@ -1,211 +1,722 @@
 package notifiers
 
 import (
+	"cmp"
+	"context"
 	"errors"
-	"os"
+	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	. "Notifier/models"
+	. "Notifier/src/sinks"
+	. "Notifier/src/store"
 	. "Notifier/src/utils"
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/transform"
 )
 
 type BaseNotifier struct {
-	Type              int
-	NoticeUrl         string
-	EnglishTopic      string
-	KoreanTopic       string
-	BoxCount          int
-	MaxNum            int
-	BoxNoticeSelector string
-	NumNoticeSelector string
-	ContentSelector   string
-	ImagesSelector    string
+	Type         int
+	NoticeUrl    string
+	EnglishTopic string
+	KoreanTopic  string
+	Sinks        []string // 새 공지를 보낼 sink 이름
+	BoxCount     int
+	MaxNum       int
+	Template     BoardTemplate
+	encoding     encoding.Encoding
+	seenNotices  map[string]string
+	pinned       map[string]Notice
+	bootstrap    bool // 저장된 BoxCount/MaxNum이 없어 첫 실행에서 기준값만 기록해야 하는지
+	state        *notifierState
 }
 
-func (BaseNotifier) New(config NotifierConfig) *BaseNotifier {
-	boxCount, maxNum := LoadDbData(config.EnglishTopic)
+// 관리 서버에서 읽는 실행 상태. BoxCount, MaxNum을 바꿀 때도 mu를 잡는다.
+// running은 같은 notifier의 실행이 겹치지 않도록 막는다. rechecking은 Recheck끼리만 막아 크롤링을 건너뛰게 하지 않는다.
+type notifierState struct {
+	mu            sync.Mutex
+	paused        bool
+	running       bool
+	rechecking    bool
+	skippedRuns   int
+	lastRunId     string
+	lastRunAt     *time.Time
+	lastError     string
+	lastDelivered *Notice
+}
+
+func (BaseNotifier) New(config NotifierConfig, template BoardTemplate) *BaseNotifier {
+	boxCount, maxNum, err := Store.LoadCursors(config.EnglishTopic)
+	bootstrap := errors.Is(err, ErrTopicNotFound)
+	if bootstrap {
+		Logger.Warn("Topic has no saved state, bootstrapping on the first crawl without sending notices", "topic", config.EnglishTopic, "type", config.Type)
+	} else if err != nil {
+		LogPanic("Failed to load notice state", err, "topic", config.EnglishTopic)
+	}
+	seenNotices, err := Store.LoadSeenNotices(config.EnglishTopic)
+	if err != nil {
+		LogPanic("Failed to load seen notices", err, "topic", config.EnglishTopic)
+	}
+	pinned, err := Store.LoadPinnedNotices(config.EnglishTopic)
+	if err != nil {
+		LogPanic("Failed to load pinned notices", err, "topic", config.EnglishTopic)
+	}
+
+	sinks := config.Sinks
+	if len(sinks) == 0 {
+		sinks = []string{DefaultSinkName}
+	}
+
+	var enc encoding.Encoding
+	if template.Encoding != "" {
+		enc, err = htmlindex.Get(template.Encoding)
+		if err != nil {
+			LogPanic("Unknown board template encoding", err, "type", template.Type, "encoding", template.Encoding)
+		}
+	}
 
 	return &BaseNotifier{
 		Type:         config.Type,
 		NoticeUrl:    config.NoticeUrl,
 		EnglishTopic: config.EnglishTopic,
 		KoreanTopic:  config.KoreanTopic,
+		Sinks:        sinks,
 		BoxCount:     boxCount,
 		MaxNum:       maxNum,
+		Template:     template,
+		encoding:     enc,
+		seenNotices:  seenNotices,
+		pinned:       pinned,
+		bootstrap:    bootstrap,
+		state:        &notifierState{},
+	}
+}
+
+// 새 공지를 notice_outbox에 넣는다. 실제 전송은 OutboxWorker가 맡는다.
+func (notifier *BaseNotifier) Notify(ctx context.Context) {
+	if notifier.isPaused() {
+		CrawlRuns.WithLabelValues(notifier.EnglishTopic, notifier.typeLabel(), "paused").Inc()
+		return
+	}
+
+	if !notifier.tryStart() {
+		CrawlRuns.WithLabelValues(notifier.EnglishTopic, notifier.typeLabel(), "skipped").Inc()
+		Logger.Warn("Skipped crawl, previous run still in progress", "topic", notifier.EnglishTopic, "type", notifier.Type)
+		return
 	}
+	defer notifier.finish()
+
+	runId := NewRunId()
+	logger := Logger.With("run_id", runId, "topic", notifier.EnglishTopic, "type", notifier.Type)
+	ctx = WithLogger(ctx, logger)
+	logger.Debug("Crawl started")
+
+	startedAt := time.Now()
+	var err error
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+			logger.Error("Crawl panicked", "error", err)
+		}
+		if err != nil {
+			// 처리하지 못한 목록 페이지가 다음 실행에서 304로 건너뛰어지지 않게 한다.
+			PageFetcher.Forget(notifier.NoticeUrl)
+		}
+		notifier.state.mu.Lock()
+		notifier.state.lastRunId = runId
+		notifier.state.lastRunAt = &startedAt
+		notifier.state.lastError = ""
+		if err != nil {
+			notifier.state.lastError = err.Error()
+		}
+		notifier.state.mu.Unlock()
+
+		result := "success"
+		if err != nil {
+			result = "error"
+		}
+		CrawlRuns.WithLabelValues(notifier.EnglishTopic, notifier.typeLabel(), result).Inc()
+		logger.Debug("Crawl finished", "result", result, "duration_ms", time.Since(startedAt).Milliseconds())
+	}()
+
+	_, err = notifier.scrapeNotice(ctx)
 }
 
-func (notifier *BaseNotifier) Notify() {
+// Notify와 같은 방식으로 목록을 읽어 새 공지를 반환한다. 실행 상태와 지표는 기록하지 않는다.
+// 운영 명령(crawl --once, dry-run)에서 쓴다.
+func (notifier *BaseNotifier) Scrape(ctx context.Context) (notices []Notice, err error) {
+	logger := Logger.With("run_id", NewRunId(), "topic", notifier.EnglishTopic, "type", notifier.Type)
+	ctx = WithLogger(ctx, logger)
 	defer func() {
-		recover()
+		if r := recover(); r != nil {
+			notices, err = nil, fmt.Errorf("%v", r)
+		}
 	}()
+	return notifier.scrapeNotice(ctx)
+}
+
+// 목록 페이지에서 번호나 게시글 번호가 id인 공지를 찾아 상세 페이지까지 읽는다.
+func (notifier *BaseNotifier) Lookup(ctx context.Context, id string) (Notice, error) {
+	doc, err := notifier.fetchDocument(ctx, notifier.NoticeUrl, "list")
+	if err != nil {
+		return Notice{}, err
+	}
+
+	for _, selector := range []string{notifier.Template.BoxNoticeSelector, notifier.Template.NumNoticeSelector} {
+		sels := doc.Find(selector)
+		for i := range sels.Nodes {
+			rowNotice := notifier.getRowNotice(sels.Eq(i))
+			if rowNotice.ID != id && notifier.getArticleId(rowNotice.Url) != id {
+				continue
+			}
+			noticeChan := make(chan Notice, 1)
+			notifier.getNotice(ctx, rowNotice, noticeChan)
+			notice := <-noticeChan
+			if notice.Url == "" {
+				return Notice{}, fmt.Errorf("failed to load notice page: %s", rowNotice.Url)
+			}
+			return notice, nil
+		}
+	}
+	return Notice{}, fmt.Errorf("notice %s not found on the list page", id)
+}
+
+func (notifier *BaseNotifier) Topic() string {
+	return notifier.EnglishTopic
+}
+
+func (notifier *BaseNotifier) Status() NotifierStatus {
+	notifier.state.mu.Lock()
+	defer notifier.state.mu.Unlock()
+
+	return NotifierStatus{
+		Type:          notifier.Type,
+		EnglishTopic:  notifier.EnglishTopic,
+		KoreanTopic:   notifier.KoreanTopic,
+		NoticeUrl:     notifier.NoticeUrl,
+		Paused:        notifier.state.paused,
+		Running:       notifier.state.running,
+		SkippedRuns:   notifier.state.skippedRuns,
+		LastRunId:     notifier.state.lastRunId,
+		LastRunAt:     notifier.state.lastRunAt,
+		LastError:     notifier.state.lastError,
+		BoxCount:      notifier.BoxCount,
+		MaxNum:        notifier.MaxNum,
+		LastDelivered: notifier.state.lastDelivered,
+	}
+}
+
+func (notifier *BaseNotifier) Pause() {
+	notifier.state.mu.Lock()
+	defer notifier.state.mu.Unlock()
+	notifier.state.paused = true
+}
+
+func (notifier *BaseNotifier) Resume() {
+	notifier.state.mu.Lock()
+	defer notifier.state.mu.Unlock()
+	notifier.state.paused = false
+}
+
+// OutboxWorker가 전송을 확인한 공지를 기록한다.
+func (notifier *BaseNotifier) Delivered(notice Notice) {
+	notifier.state.mu.Lock()
+	defer notifier.state.mu.Unlock()
+	notifier.state.lastDelivered = &notice
+}
+
+// NewDocumentFromPage를 호출하고 응답 시간과 상태 코드를 기록한다. page는 list, detail 또는 recheck이다.
+// 목록 페이지는 조건부 GET으로 요청해 바뀌지 않았으면 ErrNotModified를 반환한다.
+func (notifier *BaseNotifier) fetchDocument(ctx context.Context, url, page string) (*goquery.Document, error) {
+	logger := LoggerFrom(ctx)
+	startedAt := time.Now()
+	var doc *goquery.Document
+	var err error
+	if page == "list" {
+		doc, err = NewDocumentFromPageIfModified(ctx, url)
+	} else {
+		doc, err = NewDocumentFromPage(ctx, url)
+	}
+	FetchDuration.WithLabelValues(notifier.EnglishTopic, notifier.typeLabel(), page).Observe(time.Since(startedAt).Seconds())
+
+	code := "200"
+	var statusCodeErr *StatusCodeError
+	if errors.Is(err, ErrNotModified) {
+		code = "304"
+	} else if errors.As(err, &statusCodeErr) {
+		code = strconv.Itoa(statusCodeErr.StatusCode)
+	} else if err != nil {
+		code = "error"
+	}
+	FetchResponses.WithLabelValues(notifier.EnglishTopic, notifier.typeLabel(), page, code).Inc()
+	logger.Debug("Fetched page", "url", url, "page", page, "code", code, "duration_ms", time.Since(startedAt).Milliseconds())
 
-    notices := notifier.scrapeNotice()  // 여기서 에러가 발생하면 notices는 nil 또는 빈 슬라이스가 됨
-    if notices == nil {
-        return  // 에러가 발생한 경우 크롤링을 중단하고 빠져나감
-    }
+	return doc, err
+}
 
-    for _, notice := range notices {
-        SendCrawlingWebhook(os.Getenv("WEBHOOK_ENDPOINT"), notice)
-        SentNoticeLogger.Println(notice)
-    }
+func (notifier *BaseNotifier) typeLabel() string {
+	return strconv.Itoa(notifier.Type)
 }
 
-func (notifier *BaseNotifier) scrapeNotice() []Notice {
-    doc, err := NewDocumentFromPage(notifier.NoticeUrl)  // 에러 반환 받음
-    if err != nil {
-        ErrorLogger.Printf("Failed to load page: %s", err)  // 에러 로깅
-        return nil  // 에러 발생 시 빈 리스트 반환하거나 다른 적절한 처리
-    }
+// 이전 실행이 끝나지 않았으면 건너뛴 횟수를 올리고 false를 반환한다.
+func (notifier *BaseNotifier) tryStart() bool {
+	notifier.state.mu.Lock()
+	defer notifier.state.mu.Unlock()
+	if notifier.state.running {
+		notifier.state.skippedRuns++
+		return false
+	}
+	notifier.state.running = true
+	return true
+}
 
-    err = notifier.checkHTML(doc)
-    if err != nil {
-        ErrorLogger.Printf("HTML check failed: %s", err)
-        return nil
-    }
+func (notifier *BaseNotifier) finish() {
+	notifier.state.mu.Lock()
+	defer notifier.state.mu.Unlock()
+	notifier.state.running = false
+}
 
-    boxNotices := notifier.scrapeBoxNotice(doc)
-    numNotices := notifier.scrapeNumNotice(doc)
+// 이전 Recheck가 끝나지 않았으면 false를 반환한다. 크롤링의 skippedRuns는 올리지 않는다.
+func (notifier *BaseNotifier) tryStartRecheck() bool {
+	notifier.state.mu.Lock()
+	defer notifier.state.mu.Unlock()
+	if notifier.state.rechecking {
+		return false
+	}
+	notifier.state.rechecking = true
+	return true
+}
 
-    notices := make([]Notice, 0, len(boxNotices)+len(numNotices))
-    notices = append(notices, boxNotices...)
-    notices = append(notices, numNotices...)
+func (notifier *BaseNotifier) finishRecheck() {
+	notifier.state.mu.Lock()
+	defer notifier.state.mu.Unlock()
+	notifier.state.rechecking = false
+}
 
-    return notices
+func (notifier *BaseNotifier) isPaused() bool {
+	notifier.state.mu.Lock()
+	defer notifier.state.mu.Unlock()
+	return notifier.state.paused
+}
+
+func (notifier *BaseNotifier) scrapeNotice(ctx context.Context) ([]Notice, error) {
+	logger := LoggerFrom(ctx)
+	doc, err := notifier.fetchDocument(ctx, notifier.NoticeUrl, "list") // 에러 반환 받음
+	if errors.Is(err, ErrNotModified) {
+		logger.Debug("List page not modified", "url", notifier.NoticeUrl)
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error("Failed to load page", "url", notifier.NoticeUrl, "error", err) // 에러 로깅
+		return nil, err
+	}
+
+	err = notifier.checkHTML(doc)
+	if err != nil {
+		logger.Error("HTML check failed", "url", notifier.NoticeUrl, "error", err)
+		return nil, err
+	}
+
+	if notifier.bootstrap {
+		return nil, notifier.bootstrapTopic(ctx, doc)
+	}
+
+	// 전송 기록이 없는 topic은 기존 BoxCount/MaxNum 기준으로 이미 보낸 공지를 판단한다.
+	legacy := len(notifier.seenNotices) == 0
+
+	boxNotices := notifier.scrapeBoxNotice(ctx, doc, legacy)
+	notifier.checkPinned(ctx, doc)
+	numNotices := notifier.scrapeNumNotice(ctx, doc)
+
+	notices := make([]Notice, 0, len(boxNotices)+len(numNotices))
+	notices = append(notices, boxNotices...)
+	notices = append(notices, numNotices...)
+
+	return notices, nil
 }
 
 func (notifier *BaseNotifier) checkHTML(doc *goquery.Document) error {
 	if notifier.isInvalidHTML(doc) {
+		HTMLStructureChanged.WithLabelValues(notifier.EnglishTopic, notifier.typeLabel()).Inc()
 		errMsg := "HTML structure has changed at " + notifier.KoreanTopic
 		return errors.New(errMsg)
 	}
 	return nil
 }
 
+// 템플릿의 requiredSelectors 중 하나라도 번호 행에서 찾을 수 없으면 구조가 바뀐 것으로 본다.
 func (notifier *BaseNotifier) isInvalidHTML(doc *goquery.Document) bool {
-	switch notifier.Type {
-	case 1:
-		return Type1Notifier{}.New(notifier).isInvalidHTML(doc)
-	case 2:
-		return Type2Notifier{}.New(notifier).isInvalidHTML(doc)
-	case 3:
-		return Type3Notifier{}.New(notifier).isInvalidHTML(doc)
-	case 4:
-		return Type4Notifier{}.New(notifier).isInvalidHTML(doc)
-	case 5:
-		return Type5Notifier{}.New(notifier).isInvalidHTML(doc)
-	default:
-		return false
+	sel := doc.Find(notifier.Template.NumNoticeSelector)
+	if sel.Nodes == nil {
+		return true
 	}
+	for _, selector := range notifier.Template.RequiredSelectors {
+		if sel.Find(selector).Nodes == nil {
+			return true
+		}
+	}
+	return false
 }
 
-func (notifier *BaseNotifier) scrapeBoxNotice(doc *goquery.Document) []Notice {
-	boxNoticeSels := doc.Find(notifier.BoxNoticeSelector)
+func (notifier *BaseNotifier) scrapeBoxNotice(ctx context.Context, doc *goquery.Document, legacy bool) []Notice {
+	boxNoticeSels := doc.Find(notifier.Template.BoxNoticeSelector)
 	boxCount := boxNoticeSels.Length()
 
-	if boxCount == notifier.BoxCount {
-		return make([]Notice, 0)
-	}
-
-        if boxCount < notifier.BoxCount {
-                notifier.BoxCount = boxCount
-                query := "UPDATE notice AS n JOIN topic AS t ON n.topic_id = t.id SET n.value = ? WHERE t.department = ? AND n.type = ?"
-                _, err := DB.Exec(query, notifier.BoxCount, notifier.EnglishTopic, "box")
-                if err != nil {
-                        ErrorLogger.Panic(err)
-                }
-                return make([]Notice, 0)
-        }
-
-        boxNoticeChan := make(chan Notice, boxCount)
-        boxNotices := make([]Notice, 0, boxCount)
-        boxNoticeCount := boxCount - notifier.BoxCount
-
-        boxNoticeSels = boxNoticeSels.FilterFunction(func(i int, _ *goquery.Selection) bool {
-                return i < boxNoticeCount
-        })
-
-        boxNoticeSels.Each(func(_ int, boxNotice *goquery.Selection) {
-                go notifier.getNotice(boxNotice, boxNoticeChan)
-        })
-
-        for i := 0; i < boxNoticeCount; i++ {
-                boxNotices = append(boxNotices, <-boxNoticeChan)
-        }
-
-        notifier.BoxCount = boxCount
-        query := "UPDATE notice AS n JOIN topic AS t ON n.topic_id = t.id SET n.value = ? WHERE t.department = ? AND n.type = ?"
-        _, err := DB.Exec(query, notifier.BoxCount, notifier.EnglishTopic, "box")
-        if err != nil {
-                ErrorLogger.Panic(err)
-        }
-
-        return boxNotices
-}
-
-func (notifier *BaseNotifier) scrapeNumNotice(doc *goquery.Document) []Notice {
-        numNoticeSels := doc.Find(notifier.NumNoticeSelector)
-        maxNumText := numNoticeSels.First().Find("td:first-child").Text()
-        maxNumText = strings.TrimSpace(maxNumText)
-        maxNum, err := strconv.Atoi(maxNumText)
-        if err != nil {
-                ErrorLogger.Panic(err)
-        }
-
-        if maxNum == notifier.MaxNum {
-                return make([]Notice, 0)
-        }
-
-        if maxNum < notifier.MaxNum {
-                notifier.MaxNum = maxNum
-                query := "UPDATE notice AS n JOIN topic AS t ON n.topic_id = t.id SET n.value = ? WHERE t.department = ? AND n.type = ?"
-                _, err = DB.Exec(query, notifier.MaxNum, notifier.EnglishTopic, "num")
-                if err != nil {
-                        ErrorLogger.Panic(err)
-                }
-                return make([]Notice, 0)
-        }
-
-        numNoticeCountReference := GetNumNoticeCountReference(doc, notifier.EnglishTopic, notifier.NumNoticeSelector)
-        numNoticeCount := min(maxNum-notifier.MaxNum, numNoticeCountReference)
-        numNoticeChan := make(chan Notice, numNoticeCount)
-        numNotices := make([]Notice, 0, numNoticeCount)
-
-        numNoticeSels = numNoticeSels.FilterFunction(func(i int, _ *goquery.Selection) bool {
-                return i < numNoticeCount
-        })
-
-        numNoticeSels.Each(func(_ int, numNotice *goquery.Selection) {
-                go notifier.getNotice(numNotice, numNoticeChan)
-        })
-
-	for i := 0; i < numNoticeCount; i++ {
-		numNotices = append(numNotices, <-numNoticeChan)
-	}
-
-	notifier.MaxNum = maxNum
-	query := "UPDATE notice AS n JOIN topic AS t ON n.topic_id = t.id SET n.value = ? WHERE t.department = ? AND n.type = ?"
-	_, err = DB.Exec(query, notifier.MaxNum, notifier.EnglishTopic, "num")
+	// 기존 방식에서는 위에서부터 늘어난 개수만큼만 새 고정 공지로 보았다.
+	newBoxCount := boxCount - notifier.BoxCount
+	boxNotices := notifier.scrapeRows(ctx, boxNoticeSels, rowKind{
+		name: "box",
+		sent: func(i int, _ Notice) bool {
+			return legacy && i >= newBoxCount
+		},
+		cursor: func(_ Notice, _ []Notice) int {
+			return boxCount
+		},
+	})
+
+	// 새 공지가 있으면 전송이 확인된 뒤 OutboxWorker가 notice 테이블을 갱신한다.
+	if len(boxNotices) == 0 && boxCount != notifier.BoxCount {
+		notifier.saveCursor(ctx, "box", boxCount)
+	}
+	notifier.state.mu.Lock()
+	notifier.BoxCount = boxCount
+	notifier.state.mu.Unlock()
+
+	return boxNotices
+}
+
+func (notifier *BaseNotifier) scrapeNumNotice(ctx context.Context, doc *goquery.Document) []Notice {
+	logger := LoggerFrom(ctx)
+	numNoticeSels := doc.Find(notifier.Template.NumNoticeSelector)
+	maxNum, err := parseMaxNum(numNoticeSels)
 	if err != nil {
-		ErrorLogger.Panic(err)
+		logger.Error("Failed to parse notice number", "error", err)
+		panic(err)
+	}
+
+	// 번호가 MaxNum 이하인 행은 전송 기록이 없어도 보내지 않는다. 글이 지워져 예전 글이 목록에 다시 올라온 경우다.
+	numNotices := notifier.scrapeRows(ctx, numNoticeSels, rowKind{
+		name: "num",
+		sent: func(_ int, rowNotice Notice) bool {
+			num, err := strconv.Atoi(rowNotice.ID)
+			return err == nil && num <= notifier.MaxNum
+		},
+		// 넣지 못한 행이 있으면 그 앞 번호까지만 notice 테이블에 반영해, 다시 시작해도 MaxNum 이하로 보지 않게 한다.
+		cursor: func(notice Notice, failed []Notice) int {
+			num, _ := strconv.Atoi(notice.ID)
+			for _, row := range failed {
+				failedNum, err := strconv.Atoi(row.ID)
+				if err == nil {
+					num = min(num, failedNum-1)
+				}
+			}
+			return num
+		},
+	})
+
+	// 상세 페이지를 읽지 못해 넣지 못한 행은 다음 실행에서 다시 시도하도록 그 앞 번호까지만 MaxNum을 올린다.
+	cursor := maxNum
+	numNoticeSels.Each(func(_ int, sel *goquery.Selection) {
+		rowNotice := notifier.getRowNotice(sel)
+		num, err := strconv.Atoi(rowNotice.ID)
+		if err == nil && !notifier.isSeen(notifier.rowIdentity(rowNotice)) {
+			cursor = min(cursor, num-1)
+		}
+	})
+	cursor = max(cursor, notifier.MaxNum)
+
+	if len(numNotices) == 0 && cursor != notifier.MaxNum {
+		notifier.saveCursor(ctx, "num", cursor)
 	}
+	notifier.state.mu.Lock()
+	notifier.MaxNum = cursor
+	notifier.state.mu.Unlock()
 
 	return numNotices
 }
 
-func (notifier *BaseNotifier) getNotice(sel *goquery.Selection, noticeChan chan Notice) {
-	switch notifier.Type {
-	case 1:
-		Type1Notifier{}.New(notifier).getNotice(sel, noticeChan)
-	case 2:
-		Type2Notifier{}.New(notifier).getNotice(sel, noticeChan)
-	case 3:
-		Type3Notifier{}.New(notifier).getNotice(sel, noticeChan)
-	case 4:
-		Type4Notifier{}.New(notifier).getNotice(sel, noticeChan)
-	case 5:
-		Type5Notifier{}.New(notifier).getNotice(sel, noticeChan)
+func (notifier *BaseNotifier) saveCursor(ctx context.Context, kind string, value int) {
+	err := Store.SaveCursor(notifier.EnglishTopic, kind, value)
+	if err != nil {
+		LoggerFrom(ctx).Error("Failed to save notice state", "kind", kind, "value", value, "error", err)
+	}
+}
+
+// 번호 공지 목록의 첫 행 번호를 읽는다.
+func parseMaxNum(numNoticeSels *goquery.Selection) (int, error) {
+	maxNumText := numNoticeSels.First().Find("td:first-child").Text()
+	return strconv.Atoi(strings.TrimSpace(maxNumText))
+}
+
+// box/num 행 종류별 처리 규칙
+type rowKind struct {
+	name   string
+	sent   func(int, Notice) bool     // 전송 기록은 없지만 BoxCount/MaxNum 기준으로 이미 보낸 행인지
+	cursor func(Notice, []Notice) int // 전송이 확인된 뒤 notice 테이블에 반영할 값. 두 번째 인자는 이번에 넣지 못한 행이다.
+}
+
+// 목록 행 중 아직 전송하지 않은 공지만 상세 페이지까지 읽어 notice_outbox에 넣는다.
+// 게시글 번호가 같으면 box/num 어느 쪽으로 보냈든 같은 공지다. sent가 true인 행은 보내지 않고 전송 기록에만 남긴다.
+func (notifier *BaseNotifier) scrapeRows(ctx context.Context, sels *goquery.Selection, kind rowKind) []Notice {
+	logger := LoggerFrom(ctx)
+	noticeChan := make(chan Notice, sels.Length())
+	newCount := 0
+	// 상세 페이지를 읽으면 날짜가 바뀌므로 목록 행의 지문을 URL별로 남겨 둔다.
+	fingerprints := make(map[string]string)
+
+	sels.Each(func(i int, sel *goquery.Selection) {
+		rowNotice := notifier.getRowNotice(sel)
+		articleId, fingerprint := notifier.rowIdentity(rowNotice)
+		if notifier.isSeen(articleId, fingerprint) {
+			return
+		}
+		if kind.sent(i, rowNotice) {
+			err := notifier.markSeen(kind.name, articleId, fingerprint)
+			if err != nil {
+				logger.Error("Failed to save seen notice", "kind", kind.name, "article_id", articleId, "error", err)
+			}
+			return
+		}
+		newCount++
+		fingerprints[rowNotice.Url] = fingerprint
+		go notifier.getNotice(ctx, rowNotice, noticeChan)
+	})
+
+	// 넣을 항목의 cursor가 읽지 못한 행을 넘지 않도록 상세 페이지를 모두 읽은 뒤에 넣는다.
+	fetched := make([]Notice, 0, newCount)
+	failed := make([]Notice, 0)
+	for i := 0; i < newCount; i++ {
+		notice := <-noticeChan
+		if notice.Url == "" {
+			// 상세 페이지를 읽지 못한 공지는 다음 실행에서 다시 시도
+			PageFetcher.Forget(notifier.NoticeUrl)
+			failed = append(failed, notice)
+			continue
+		}
+		fetched = append(fetched, notice)
+	}
+
+	// 앞 번호부터 넣어, 넣지 못한 행이 있으면 그 뒤 행의 cursor도 함께 묶는다.
+	slices.SortStableFunc(fetched, func(a, b Notice) int {
+		return cmp.Compare(kind.cursor(a, nil), kind.cursor(b, nil))
+	})
+	notices := make([]Notice, 0, len(fetched))
+	for _, notice := range fetched {
+		err := notifier.enqueue(kind, notice, fingerprints[notice.Url], kind.cursor(notice, failed))
+		if err != nil {
+			logger.Error("Failed to enqueue notice", "kind", kind.name, "notice_id", notice.ID, "url", notice.Url, "error", err)
+			PageFetcher.Forget(notifier.NoticeUrl)
+			failed = append(failed, notice)
+			continue
+		}
+		logger.Info("Notice queued", "kind", kind.name, "notice_id", notice.ID, "url", notice.Url, "title", notice.Title)
+		notices = append(notices, notice)
+	}
+	NoticesScraped.WithLabelValues(notifier.EnglishTopic, notifier.typeLabel(), kind.name).Add(float64(len(notices)))
+
+	return notices
+}
+
+// sink마다 항목을 하나씩 넣는다.
+func (notifier *BaseNotifier) enqueue(kind rowKind, notice Notice, fingerprint string, cursor int) error {
+	articleId := notifier.getArticleId(notice.Url)
+	entries := make([]OutboxEntry, 0, len(notifier.Sinks))
+	for _, sink := range notifier.Sinks {
+		entries = append(entries, OutboxEntry{
+			Topic:       notifier.EnglishTopic,
+			Kind:        kind.name,
+			ArticleId:   articleId,
+			Sink:        sink,
+			Fingerprint: fingerprint,
+			Cursor:      cursor,
+			Notice:      notice,
+		})
+	}
+	err := Store.EnqueueNotices(entries)
+	if err != nil {
+		return err
+	}
+	notifier.seenNotices[SeenNoticeKey(kind.name, articleId)] = fingerprint
+	return nil
+}
+
+// 고정 공지로 보냈든 번호 공지로 보냈든 같은 글의 전송 기록이 있는지 확인한다.
+// 게시글 번호가 같아도 지문이 다르면 번호가 다른 글에 다시 쓰인 것이다. 어느 한쪽 지문을 모르면 번호만 비교한다.
+func (notifier *BaseNotifier) isSeen(articleId, fingerprint string) bool {
+	for _, kind := range []string{"box", "num"} {
+		seen, ok := notifier.seenNotices[SeenNoticeKey(kind, articleId)]
+		if ok && (seen == "" || fingerprint == "" || seen == fingerprint) {
+			return true
+		}
+	}
+	return false
+}
+
+// 보내지 않을 공지를 전송 기록에 남긴다.
+func (notifier *BaseNotifier) markSeen(kind, articleId, fingerprint string) error {
+	err := Store.SaveSeenNotice(notifier.EnglishTopic, kind, articleId, fingerprint)
+	if err != nil {
+		return err
+	}
+	notifier.seenNotices[SeenNoticeKey(kind, articleId)] = fingerprint
+	return nil
+}
+
+// 목록 행에서 읽을 수 있는 필드만 채운 Notice를 만든다.
+func (notifier *BaseNotifier) getRowNotice(sel *goquery.Selection) Notice {
+	fields := notifier.Template.Fields
+
+	return Notice{
+		ID:           notifier.getField(sel, fields["id"]),
+		Category:     notifier.getField(sel, fields["category"]),
+		Title:        notifier.getField(sel, fields["title"]),
+		Department:   notifier.getField(sel, fields["department"]),
+		Date:         notifier.parseDate(notifier.getField(sel, fields["date"])),
+		ModifiedDate: notifier.parseDate(notifier.getField(sel, fields["modifiedDate"])),
+		Url:          notifier.getUrl(sel),
+		EnglishTopic: notifier.EnglishTopic,
+		KoreanTopic:  notifier.KoreanTopic,
+	}
+}
+
+// 상세 페이지를 읽어 본문(평문, HTML, Markdown), 이미지, 첨부파일, 날짜를 채운다.
+func (notifier *BaseNotifier) getNotice(ctx context.Context, notice Notice, noticeChan chan Notice) {
+	logger := LoggerFrom(ctx)
+	crawledAt := time.Now()
+
+	doc, err := notifier.fetchDocument(ctx, notice.Url, "detail")
+	if err != nil {
+		logger.Error("Failed to load notice page", "notice_id", notice.ID, "url", notice.Url, "error", err)
+		noticeChan <- Notice{ID: notice.ID} // 에러 발생 시 번호만 채운 Notice 반환
+		return
+	}
+
+	content := notifier.getContent(doc)
+
+	sel := doc.Find(notifier.Template.ImagesSelector)
+	images := make([]string, 0, sel.Length())
+	sel.Each(func(_ int, s *goquery.Selection) {
+		image, _ := s.Attr("src")
+		if strings.Contains(image, "base64,") {
+			return
+		}
+		if strings.Contains(image, "fonts.gstatic.com") {
+			return
+		}
+		if !strings.Contains(image, "http://") && !strings.Contains(image, "https://") {
+			image = notifier.Template.ImageHost + image
+		}
+		images = append(images, image)
+	})
+
+	notifier.setDetailDates(doc, &notice, crawledAt)
+	notice.Content = content
+	body := notifier.getBody(doc, notice.Url)
+	notice.ContentHTML, notice.ContentMD, notice.Tables = renderHTML(body), renderMarkdown(body), getTables(body)
+	notice.Images = images
+	notice.Attachments = notifier.getAttachments(doc, notice.Url)
+
+	noticeChan <- notice
+}
+
+// ContentSelector에 맞는 요소의 글자를 문서 순서대로 \n으로 잇는다.
+// 본문 안의 표는 칸을 한 줄로 뭉개지 않도록 그 자리에 GFM 표로 쓰고, 표 안에서 맞는 요소는 건너뛴다.
+func (notifier *BaseNotifier) getContent(doc *goquery.Document) string {
+	sel := doc.Find(notifier.Template.ContentSelector)
+	matched := make(map[*html.Node]int, sel.Length())
+	for i, node := range sel.Nodes {
+		matched[node] = i
+	}
+	bodies := make(map[*html.Node]bool)
+	if notifier.Template.BodySelector != "" {
+		for _, node := range doc.Find(notifier.Template.BodySelector).Nodes {
+			bodies[node] = true
+		}
+	}
+
+	contents := make([]string, 0, sel.Length())
+	var visit func(node *html.Node, inBody bool)
+	visit = func(node *html.Node, inBody bool) {
+		inBody = inBody || bodies[node]
+		if inBody && node.Type == html.ElementNode && node.Data == "table" {
+			grid := newTableGrid(node)
+			if !grid.isLayout() {
+				contents = append(contents, textTable(grid, func(cell *html.Node) string {
+					return strings.ReplaceAll(notifier.decode(plainText(cell)), "|", `\|`)
+				})...)
+				return
+			}
+		}
+		if i, ok := matched[node]; ok {
+			text := sel.Eq(i).Text()
+			if text != "" && text != "\u00a0" {
+				str := strings.ReplaceAll(text, "\u00a0", " ")
+				str = notifier.decode(str)
+				str = strings.ReplaceAll(str, "\n\n", "\\n")
+				str = strings.ReplaceAll(str, "\n", "\\n")
+				contents = append(contents, strings.TrimSpace(str))
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			visit(child, inBody)
+		}
+	}
+	for _, node := range doc.Nodes {
+		visit(node, false)
+	}
+	return strings.Join(contents, "\\n")
+}
+
+// 템플릿 규칙에 따라 목록 행에서 필드 값을 읽는다. 규칙이 없으면 빈 문자열을 반환한다.
+func (notifier *BaseNotifier) getField(sel *goquery.Selection, rule FieldRule) string {
+	if rule.Selector == "" {
+		return ""
+	}
+	if rule.IfExists != "" && sel.Find(rule.IfExists).Nodes != nil {
+		return rule.Value
+	}
+
+	var value string
+	if rule.Attr != "" {
+		value, _ = sel.Find(rule.Selector).Attr(rule.Attr)
+	} else {
+		value = sel.Find(rule.Selector).Text()
+	}
+	value = notifier.decode(value)
+	value = strings.TrimSuffix(value, rule.TrimSuffix)
+	return strings.TrimSpace(value)
+}
+
+// 템플릿의 url 규칙으로 목록 링크를 상세 페이지 URL로 바꾼다.
+func (notifier *BaseNotifier) getUrl(sel *goquery.Selection) string {
+	rule := notifier.Template.Url
+	href, _ := sel.Find(rule.Selector).Attr(rule.Attr)
+	split := strings.FieldsFunc(href, func(c rune) bool {
+		return strings.ContainsRune(rule.Separator, c)
+	})
+
+	var parts string
+	if rule.From < len(split) {
+		parts = strings.Join(split[rule.From:min(rule.To, len(split))], rule.Separator)
+	}
+	noticeUrl := strings.TrimSuffix(notifier.NoticeUrl, rule.TrimNoticeUrlSuffix)
+
+	url := strings.ReplaceAll(rule.Format, "{noticeUrl}", noticeUrl)
+	url = strings.ReplaceAll(url, "{parts}", parts)
+	return url
+}
+
+func (notifier *BaseNotifier) decode(str string) string {
+	if notifier.encoding == nil {
+		return str
 	}
+	str, _, _ = transform.String(notifier.encoding.NewDecoder(), str)
+	return str
 }
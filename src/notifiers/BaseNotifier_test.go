@@ -0,0 +1,388 @@
+package notifiers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	. "Notifier/models"
+	. "Notifier/src/store"
+)
+
+// 목록 페이지 요청을 release가 닫힐 때까지 붙잡아 두는 서버
+func newBlockingServer(release <-chan struct{}, requests *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		<-release
+		// 셀렉터와 맞지 않는 페이지라 checkHTML에서 멈추고 DB에는 접근하지 않는다.
+		w.Write([]byte("<html><body></body></html>"))
+	}))
+}
+
+func newTestNotifier(noticeUrl string) *BaseNotifier {
+	return &BaseNotifier{
+		Type:         1,
+		NoticeUrl:    noticeUrl,
+		EnglishTopic: "Test",
+		KoreanTopic:  "테스트",
+		Sinks:        []string{"backend"},
+		Template:     BoardTemplate{NumNoticeSelector: "table > tbody > tr"},
+		seenNotices:  make(map[string]string),
+		state:        &notifierState{},
+	}
+}
+
+func TestNotifySkipsOverlappingRuns(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int32
+	server := newBlockingServer(release, &requests)
+	defer server.Close()
+
+	notifier := newTestNotifier(server.URL)
+
+	// 첫 실행이 목록 페이지를 기다리는 동안 나머지 실행은 모두 건너뛰어야 한다.
+	var first sync.WaitGroup
+	first.Add(1)
+	go func() {
+		defer first.Done()
+		notifier.Notify(context.Background())
+	}()
+	for requests.Load() == 0 {
+		runtime.Gosched()
+	}
+
+	const overlapping = 10
+	var others sync.WaitGroup
+	for i := 0; i < overlapping; i++ {
+		others.Add(1)
+		go func() {
+			defer others.Done()
+			notifier.Notify(context.Background())
+			_ = notifier.Status()
+		}()
+	}
+	others.Wait()
+
+	status := notifier.Status()
+	if !status.Running {
+		t.Error("expected notifier to be running while the first crawl is in flight")
+	}
+	if status.SkippedRuns != overlapping {
+		t.Errorf("skipped runs = %d, want %d", status.SkippedRuns, overlapping)
+	}
+
+	close(release)
+	first.Wait()
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("list page requested %d times, want 1", got)
+	}
+
+	// 실행이 끝나면 다음 실행은 다시 진행된다.
+	notifier.Notify(context.Background())
+	if got := requests.Load(); got != 2 {
+		t.Errorf("list page requested %d times after the first run finished, want 2", got)
+	}
+	status = notifier.Status()
+	if status.Running {
+		t.Error("expected notifier to be idle after runs finished")
+	}
+	if status.LastError == "" {
+		t.Error("expected the HTML check failure to be recorded as the last error")
+	}
+}
+
+func TestNotifySkipsPausedNotifier(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	close(release)
+	server := newBlockingServer(release, &requests)
+	defer server.Close()
+
+	notifier := newTestNotifier(server.URL)
+	notifier.Pause()
+	notifier.Notify(context.Background())
+	if got := requests.Load(); got != 0 {
+		t.Errorf("paused notifier requested the list page %d times", got)
+	}
+
+	notifier.Resume()
+	notifier.Notify(context.Background())
+	if got := requests.Load(); got != 1 {
+		t.Errorf("resumed notifier requested the list page %d times, want 1", got)
+	}
+}
+
+// 저장된 상태가 없는 topic은 첫 실행에서 기준값만 기록하고 아무것도 보내지 않아야 한다.
+func TestNotifyBootstrapsNewTopic(t *testing.T) {
+	Store = MemoryStore{}.New()
+	template := loadFixtureTemplate(t, "ajou-cms")
+	server := newFixtureServer(filepath.Join("testdata", "ajou-cms"), template.ArticleIdParam)
+	defer server.Close()
+
+	notifier := newFixtureNotifier(t, server.URL+"/kr/ajou/notice.do", template)
+	notifier.bootstrap = true
+
+	for run := 1; run <= 2; run++ {
+		notifier.Notify(context.Background())
+		if status := notifier.Status(); status.LastError != "" {
+			t.Fatalf("run %d failed: %s", run, status.LastError)
+		}
+		entries, err := Store.LoadDueOutboxEntries(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("run %d queued %d notices, want none", run, len(entries))
+		}
+	}
+
+	boxCount, maxNum, err := Store.LoadCursors(notifier.EnglishTopic)
+	if err != nil || boxCount != 1 || maxNum != 1203 {
+		t.Errorf("saved state = (%d, %d, %v), want (1, 1203, nil)", boxCount, maxNum, err)
+	}
+	seen, _ := Store.LoadSeenNotices(notifier.EnglishTopic)
+	if len(seen) != 3 {
+		t.Errorf("seen notices = %v, want every row on the list page", seen)
+	}
+}
+
+// ajou-cms 픽스처를 dir에 복사하고 목록 페이지를 바꾼다. 고정 공지 334455는 번호 1190 행으로 내려가고,
+// 글이 지워져 예전 글 1180이 목록에 올라오고, 상세 페이지가 없는 새 글 1204가 맨 위에 생긴다.
+func writeShuffledList(t *testing.T, dir string) {
+	src := filepath.Join("testdata", "ajou-cms")
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		page, err := os.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry.Name() == "list.html" {
+			list := string(page)
+			start := strings.Index(list, `<tr class="b-top-box">`)
+			end := start + strings.Index(list[start:], "</tr>\n") + len("</tr>\n")
+			pinned := strings.Replace(strings.Replace(list[start:end], `<tr class="b-top-box">`, "<tr>", 1), "<td>공지</td>", "<td>1190</td>", 1)
+			newest := strings.NewReplacer("<td>1203</td>", "<td>1204</td>", "articleNo=334470", "articleNo=334480").Replace(list[end : end+strings.Index(list[end:], "</tr>\n")+len("</tr>\n")])
+			older := strings.NewReplacer("<td>1190</td>", "<td>1180</td>", "articleNo=334455", "articleNo=334400").Replace(pinned)
+			list = list[:start] + newest + list[end:]
+			list = strings.Replace(list, "</tbody>", pinned+older+"</tbody>", 1)
+			page = []byte(list)
+		}
+		err = os.WriteFile(filepath.Join(dir, entry.Name()), page, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// 번호 공지로 내려간 고정 공지와 목록에 다시 올라온 예전 글은 보내지 않고, 상세 페이지를 읽지 못한 새 글은 다음 실행에서 보낸다.
+func TestScrapeNoticeSendsOnlyNewArticles(t *testing.T) {
+	Store = MemoryStore{}.New()
+	dir := t.TempDir()
+	writeShuffledList(t, dir)
+	template := loadFixtureTemplate(t, "ajou-cms")
+	server := newFixtureServer(dir, template.ArticleIdParam)
+	defer server.Close()
+
+	notifier := newFixtureNotifier(t, server.URL+"/kr/ajou/notice.do", template)
+	notifier.BoxCount, notifier.MaxNum = 1, 1203
+	for _, key := range []string{"box:334455", "num:334470", "num:334468"} {
+		notifier.seenNotices[key] = ""
+	}
+
+	ctx := context.Background()
+	notices, err := notifier.scrapeNotice(ctx)
+	if err != nil || len(notices) != 0 {
+		t.Fatalf("first run = (%+v, %v), want nothing while the new article page is missing", notices, err)
+	}
+	if notifier.MaxNum != 1203 {
+		t.Errorf("MaxNum = %d, want 1203 until 1204 is queued", notifier.MaxNum)
+	}
+
+	detail, err := os.ReadFile(filepath.Join(dir, "detail-334470.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "detail-334480.html"), detail, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notices, err = notifier.scrapeNotice(ctx)
+	if err != nil || len(notices) != 1 || notices[0].ID != "1204" {
+		t.Fatalf("second run = (%+v, %v), want only 1204", notices, err)
+	}
+
+	due, _ := Store.LoadDueOutboxEntries(10)
+	if len(due) != 1 || due[0].ArticleId != "334480" {
+		t.Errorf("due entries = %+v, want only 334480", due)
+	}
+	seen, _ := Store.LoadSeenNotices(notifier.EnglishTopic)
+	_, recorded := seen["num:334400"]
+	_, moved := seen["num:334455"]
+	if !recorded || moved {
+		t.Errorf("seen notices = %v, want 334400 recorded and 334455 left as box", seen)
+	}
+}
+
+// 1202의 상세 페이지를 읽지 못한 채 1203이 전송되어도 저장된 MaxNum은 1201에 머물러, 다시 시작한 뒤 1202를 보낸다.
+func TestFailedRowIsSentAfterRestart(t *testing.T) {
+	Store = MemoryStore{}.New()
+	dir := t.TempDir()
+	src := filepath.Join("testdata", "ajou-cms")
+	for _, name := range []string{"list.html", "detail-334455.html", "detail-334470.html"} {
+		page, err := os.ReadFile(filepath.Join(src, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, name), page, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	template := loadFixtureTemplate(t, "ajou-cms")
+	server := newFixtureServer(dir, template.ArticleIdParam)
+	defer server.Close()
+
+	config := NotifierConfig{Type: template.Type, EnglishTopic: "Test", KoreanTopic: "테스트", NoticeUrl: server.URL + "/kr/ajou/notice.do"}
+	mustSucceed := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustSucceed(Store.SaveCursor("Test", "box", 1))
+	mustSucceed(Store.SaveCursor("Test", "num", 1201))
+	mustSucceed(Store.SaveSeenNotice("Test", "box", "334455", ""))
+
+	ctx := context.Background()
+	notices, err := BaseNotifier{}.New(config, template).scrapeNotice(ctx)
+	if err != nil || len(notices) != 1 || notices[0].ID != "1203" {
+		t.Fatalf("first run = (%+v, %v), want only 1203", notices, err)
+	}
+	due, _ := Store.LoadDueOutboxEntries(10)
+	if len(due) != 1 || due[0].Cursor != 1201 {
+		t.Fatalf("due entries = %+v, want 1203 holding the cursor at 1201", due)
+	}
+	mustSucceed(Store.MarkOutboxSent(due[0]))
+
+	detail, err := os.ReadFile(filepath.Join(src, "detail-334468.html"))
+	mustSucceed(err)
+	mustSucceed(os.WriteFile(filepath.Join(dir, "detail-334468.html"), detail, 0o644))
+
+	notices, err = BaseNotifier{}.New(config, template).scrapeNotice(ctx)
+	if err != nil || len(notices) != 1 || notices[0].ID != "1202" {
+		t.Fatalf("run after restart = (%+v, %v), want 1202", notices, err)
+	}
+}
+
+// 보낸 글의 제목이 바뀌어도 새 공지로 보지 않고, 게시글 번호가 다른 글에 다시 쓰이면 새 공지로 보낸다.
+func TestScrapeNoticeComparesFingerprints(t *testing.T) {
+	Store = MemoryStore{}.New()
+	dir := t.TempDir()
+	src := filepath.Join("testdata", "ajou-cms")
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		page, err := os.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, entry.Name()), page, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := os.ReadFile(filepath.Join(src, "list.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeList := func(replacer *strings.Replacer) {
+		t.Helper()
+		err := os.WriteFile(filepath.Join(dir, "list.html"), []byte(replacer.Replace(string(list))), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	template := loadFixtureTemplate(t, "ajou-cms")
+	server := newFixtureServer(dir, template.ArticleIdParam)
+	defer server.Close()
+
+	notifier := newFixtureNotifier(t, server.URL+"/kr/ajou/notice.do", template)
+	notifier.bootstrap = true
+	ctx := context.Background()
+	_, err = notifier.scrapeNotice(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier.bootstrap = false
+
+	writeList(strings.NewReplacer(">교내 근로장학생 모집<", ">교내 근로장학생 모집 (기간 연장)<"))
+	notices, err := notifier.scrapeNotice(ctx)
+	if err != nil || len(notices) != 0 {
+		t.Fatalf("run after title edit = (%+v, %v), want nothing", notices, err)
+	}
+
+	writeList(strings.NewReplacer("<td>1203</td>", "<td>1204</td>", "<td>24.08.20</td>", "<td>24.09.02</td>"))
+	notices, err = notifier.scrapeNotice(ctx)
+	if err != nil || len(notices) != 1 || notices[0].ID != "1204" {
+		t.Fatalf("run after article number reuse = (%+v, %v), want 1204", notices, err)
+	}
+	due, _ := Store.LoadDueOutboxEntries(10)
+	if len(due) != 1 || due[0].ArticleId != "334470" || due[0].Fingerprint == "" {
+		t.Errorf("due entries = %+v, want 334470 with the new fingerprint", due)
+	}
+}
+
+// 목록 페이지를 처리하지 못한 실행 뒤에는 저장한 ETag를 지워, 다음 실행이 304로 건너뛰지 않고 다시 읽는다.
+func TestNotifyForgetsValidatorsAfterFailedRun(t *testing.T) {
+	Store = MemoryStore{}.New()
+	var broken atomic.Bool
+	broken.Store(true)
+	var validators []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		validators = append(validators, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if broken.Load() {
+			w.Write([]byte("<html><body>점검 중</body></html>"))
+			return
+		}
+		w.Write([]byte("<table><tbody><tr><td>1</td></tr></tbody></table>"))
+	}))
+	defer server.Close()
+
+	// 상세 페이지를 읽지 않도록 처음 실행하는 topic으로 둔다.
+	notifier := newTestNotifier(server.URL)
+	notifier.bootstrap = true
+	ctx := context.Background()
+	notifier.Notify(ctx)
+	if notifier.Status().LastError == "" {
+		t.Fatal("first run succeeded on a page without notice rows")
+	}
+	broken.Store(false)
+	notifier.Notify(ctx)
+	if notifier.Status().LastError != "" {
+		t.Fatalf("second run failed: %s", notifier.Status().LastError)
+	}
+	notifier.Notify(ctx)
+
+	want := []string{"", "", `"v1"`}
+	if strings.Join(validators, ",") != strings.Join(want, ",") {
+		t.Errorf("If-None-Match per run = %q, want %q", validators, want)
+	}
+}
@@ -0,0 +1,182 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "Notifier/models"
+	. "Notifier/src/utils"
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+var update = flag.Bool("update", false, "testdata의 golden 파일을 현재 결과로 다시 쓴다")
+
+func TestMain(m *testing.M) {
+	// 로컬 fixture 서버에는 호스트별 요청 간격을 두지 않는다.
+	PageFetcher = Fetcher{}.New(FetcherConfig{DefaultPolicy: HostPolicy{DelayMs: 1, Burst: 100}}, HTTPClient)
+	os.Exit(m.Run())
+}
+
+// testdata 아래 디렉터리 이름은 boardTemplates.json의 name과 같다.
+var fixtureBoards = []struct {
+	template string
+	listPath string // 실제 사이트의 목록 페이지 경로
+}{
+	{"ajou-cms", "/kr/ajou/notice.do"},
+	{"software-bbs", "/bbs/board.php?tbl=notice"},
+	{"ajoumc-nursing", "/nursing/board/commBoardNoticeList.do"},
+	{"ajoumc-medicine", "/medicine/board/commBoardUVNoticeList.do"},
+	{"ajou-cms-no-category", "/security/board/under-notice.do"},
+}
+
+// golden 파일의 한 항목
+type fixtureNotice struct {
+	ArticleId string `json:"articleId"`
+	Notice    Notice `json:"notice"`
+}
+
+// 저장해 둔 페이지로 실제 사이트를 대신한다.
+// 게시글 번호 파라미터가 있으면 detail-<번호>.html, 없으면 list.html을 돌려준다.
+func newFixtureServer(dir, articleIdParam string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := "list.html"
+		if articleId := r.URL.Query().Get(articleIdParam); articleId != "" {
+			name = "detail-" + articleId + ".html"
+		}
+		page, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(page)
+	}))
+}
+
+func loadFixtureTemplate(t *testing.T, name string) BoardTemplate {
+	for _, template := range LoadBoardTemplates("../../config/boardTemplates.json") {
+		if template.Name == name {
+			return template
+		}
+	}
+	t.Fatalf("board template %q not found", name)
+	return BoardTemplate{}
+}
+
+func newFixtureNotifier(t *testing.T, noticeUrl string, template BoardTemplate) *BaseNotifier {
+	notifier := newTestNotifier(noticeUrl)
+	notifier.Type = template.Type
+	notifier.Template = template
+	if template.Encoding != "" {
+		enc, err := htmlindex.Get(template.Encoding)
+		if err != nil {
+			t.Fatalf("unknown encoding %q: %v", template.Encoding, err)
+		}
+		notifier.encoding = enc
+	}
+	return notifier
+}
+
+// scrapeNotice와 같은 순서로 고정 공지와 번호 공지를 읽는다. DB에는 기록하지 않는다.
+func scrapeFixture(t *testing.T, notifier *BaseNotifier) []fixtureNotice {
+	ctx := context.Background()
+	doc, err := notifier.fetchDocument(ctx, notifier.NoticeUrl, "list")
+	if err != nil {
+		t.Fatalf("failed to load list page: %v", err)
+	}
+	if notifier.isInvalidHTML(doc) {
+		t.Fatal("list page rejected by isInvalidHTML")
+	}
+
+	notices := make([]fixtureNotice, 0)
+	for _, selector := range []string{notifier.Template.BoxNoticeSelector, notifier.Template.NumNoticeSelector} {
+		doc.Find(selector).Each(func(_ int, sel *goquery.Selection) {
+			noticeChan := make(chan Notice, 1)
+			notifier.getNotice(ctx, notifier.getRowNotice(sel), noticeChan)
+			notice := <-noticeChan
+			if notice.Url == "" {
+				t.Errorf("failed to load detail page for row %q", sel.Text())
+				return
+			}
+			notice.CrawledAt = "" // 실행 시각이라 비교하지 않는다.
+			notices = append(notices, fixtureNotice{ArticleId: notifier.getArticleId(notice.Url), Notice: notice})
+		})
+	}
+	return notices
+}
+
+func TestBoardTemplatesAgainstFixtures(t *testing.T) {
+	for _, board := range fixtureBoards {
+		t.Run(board.template, func(t *testing.T) {
+			template := loadFixtureTemplate(t, board.template)
+			dir := filepath.Join("testdata", board.template)
+			server := newFixtureServer(dir, template.ArticleIdParam)
+			defer server.Close()
+
+			notifier := newFixtureNotifier(t, server.URL+board.listPath, template)
+			notices := scrapeFixture(t, notifier)
+
+			var buf bytes.Buffer
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+			err := encoder.Encode(notices)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 서버 주소는 실행마다 바뀌므로 golden 파일에는 {server}로 남긴다.
+			got := bytes.ReplaceAll(buf.Bytes(), []byte(server.URL), []byte("{server}"))
+
+			golden := filepath.Join(dir, "notices.golden.json")
+			if *update {
+				err := os.WriteFile(golden, got, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("notices differ from %s\ngot:\n%s\nwant:\n%s", golden, got, want)
+			}
+		})
+	}
+}
+
+// 목록 행을 찾을 수 없는 페이지는 구조가 바뀐 것으로 판단해야 한다.
+func TestIsInvalidHTMLRejectsChangedLayout(t *testing.T) {
+	for _, board := range fixtureBoards {
+		t.Run(board.template, func(t *testing.T) {
+			notifier := newFixtureNotifier(t, "", loadFixtureTemplate(t, board.template))
+
+			pages, err := filepath.Glob(filepath.Join("testdata", board.template, "*.html"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, page := range pages {
+				file, err := os.Open(page)
+				if err != nil {
+					t.Fatal(err)
+				}
+				doc, err := goquery.NewDocumentFromReader(file)
+				file.Close()
+				if err != nil {
+					t.Fatal(err)
+				}
+				wantInvalid := filepath.Base(page) != "list.html"
+				if got := notifier.isInvalidHTML(doc); got != wantInvalid {
+					t.Errorf("isInvalidHTML(%s) = %v, want %v", page, got, wantInvalid)
+				}
+			}
+		})
+	}
+}
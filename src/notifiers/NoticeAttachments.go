@@ -0,0 +1,51 @@
+package notifiers
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	. "Notifier/models"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 템플릿의 attachments 규칙으로 상세 페이지의 첨부파일을 읽는다. 링크는 상세 페이지 주소 기준의 절대 주소로 바꾼다.
+func (notifier *BaseNotifier) getAttachments(doc *goquery.Document, noticeUrl string) []Attachment {
+	rule := notifier.Template.Attachments
+	attachments := make([]Attachment, 0)
+	if rule.Selector == "" {
+		return attachments
+	}
+	base, err := url.Parse(noticeUrl)
+	if err != nil {
+		return attachments
+	}
+
+	doc.Find(rule.Selector).Each(func(_ int, sel *goquery.Selection) {
+		link := sel
+		if rule.Link != "" {
+			link = sel.Find(rule.Link).First()
+		}
+		href, ok := link.Attr("href")
+		if !ok || strings.HasPrefix(href, "javascript:") {
+			return
+		}
+		fileUrl, err := base.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+
+		name := notifier.getField(sel, rule.Name)
+		if name == "" {
+			name = strings.TrimSpace(notifier.decode(link.Text()))
+		}
+		size := strings.Trim(notifier.getField(sel, rule.Size), "() ")
+		attachments = append(attachments, Attachment{
+			Name:      name,
+			Url:       fileUrl.String(),
+			Size:      size,
+			Extension: strings.ToLower(strings.TrimPrefix(path.Ext(name), ".")),
+		})
+	})
+	return attachments
+}
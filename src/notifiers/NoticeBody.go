@@ -0,0 +1,368 @@
+package notifiers
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// 본문에 남기는 태그와 태그별로 남기는 속성. 목록에 없는 태그는 벗겨 내고 내용만 남긴다.
+var bodyTags = map[string][]string{
+	"p": nil, "div": nil, "br": nil, "hr": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "em": nil, "u": nil, "s": nil, "sup": nil, "sub": nil, "code": nil, "pre": nil,
+	"a": {"href"}, "img": {"src", "alt"},
+	"ul": nil, "ol": nil, "li": nil, "blockquote": nil,
+	"table": nil, "caption": nil, "thead": nil, "tbody": nil, "tfoot": nil, "tr": nil,
+	"th": {"colspan", "rowspan"}, "td": {"colspan", "rowspan"},
+}
+
+// 같은 뜻의 태그는 하나로 맞춘다.
+var bodyTagAliases = map[string]string{"b": "strong", "i": "em", "strike": "s", "del": "s"}
+
+// 내용까지 버리는 태그
+var droppedBodyTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "iframe": true, "object": true, "embed": true,
+	"form": true, "input": true, "button": true, "select": true, "textarea": true, "svg": true,
+	"head": true, "title": true, "meta": true, "link": true,
+}
+
+// 템플릿의 bodySelector로 찾은 본문에서 허용한 태그만 남긴 트리를 만든다. bodySelector가 없으면 nil이다.
+// 링크와 이미지는 상세 페이지 주소 기준의 절대 주소로 바꾸고 http, https(링크는 mailto도)만 남긴다.
+func (notifier *BaseNotifier) getBody(doc *goquery.Document, noticeUrl string) *html.Node {
+	if notifier.Template.BodySelector == "" {
+		return nil
+	}
+	base, err := url.Parse(noticeUrl)
+	if err != nil {
+		return nil
+	}
+
+	root := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, node := range doc.Find(notifier.Template.BodySelector).Nodes {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			notifier.sanitize(child, root, base)
+		}
+	}
+	return root
+}
+
+// getBody로 만든 본문을 HTML로 쓴다. 감싸는 root는 빼고 자식만 쓴다.
+func renderHTML(root *html.Node) string {
+	if root == nil {
+		return ""
+	}
+	var buf strings.Builder
+	for child := root.FirstChild; child != nil; child = child.NextSibling {
+		err := html.Render(&buf, child)
+		if err != nil {
+			return ""
+		}
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// node를 정리해 parent 아래에 붙인다.
+func (notifier *BaseNotifier) sanitize(node, parent *html.Node, base *url.URL) {
+	switch node.Type {
+	case html.TextNode:
+		text := notifier.decode(strings.ReplaceAll(node.Data, "\u00a0", " "))
+		// 버린 요소 사이에 남은 공백은 앞의 공백과 합친다.
+		if last := parent.LastChild; last != nil && last.Type == html.TextNode && strings.TrimSpace(last.Data) == "" && strings.TrimSpace(text) == "" {
+			last.Data = "\n"
+			return
+		}
+		parent.AppendChild(&html.Node{Type: html.TextNode, Data: text})
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	tag := strings.ToLower(node.Data)
+	if alias, ok := bodyTagAliases[tag]; ok {
+		tag = alias
+	}
+	if droppedBodyTags[tag] {
+		return
+	}
+	allowedAttrs, ok := bodyTags[tag]
+	if !ok {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			notifier.sanitize(child, parent, base)
+		}
+		return
+	}
+
+	element := &html.Node{Type: html.ElementNode, Data: tag, DataAtom: atom.Lookup([]byte(tag))}
+	for _, name := range allowedAttrs {
+		value, ok := bodyAttr(node, name)
+		if !ok {
+			continue
+		}
+		switch name {
+		case "href":
+			value, ok = absoluteUrl(base, value, "http", "https", "mailto")
+		case "src":
+			value, ok = absoluteUrl(base, value, "http", "https")
+		case "colspan", "rowspan":
+			span, err := strconv.Atoi(strings.TrimSpace(value))
+			value, ok = strconv.Itoa(span), err == nil && span > 1 && span <= 1000
+		default:
+			value = notifier.decode(value)
+		}
+		if ok {
+			element.Attr = append(element.Attr, html.Attribute{Key: name, Val: value})
+		}
+	}
+	// 주소가 없는 이미지는 버리고, 주소가 없는 링크는 내용만 남긴다.
+	if _, ok := bodyAttr(element, "src"); tag == "img" && !ok {
+		return
+	}
+	if _, ok := bodyAttr(element, "href"); tag == "a" && !ok {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			notifier.sanitize(child, parent, base)
+		}
+		return
+	}
+
+	parent.AppendChild(element)
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		notifier.sanitize(child, element, base)
+	}
+	// 빈 문단처럼 내용이 남지 않은 요소는 버린다. 표의 칸은 자리를 지키도록 남긴다.
+	if tag != "td" && tag != "th" && isBlank(element) {
+		parent.RemoveChild(element)
+	}
+}
+
+// 글자, 이미지, 줄바꿈, 구분선이 하나도 없는 요소인지 확인한다.
+func isBlank(node *html.Node) bool {
+	switch node.Type {
+	case html.TextNode:
+		return strings.TrimSpace(node.Data) == ""
+	case html.ElementNode:
+		if node.Data == "img" || node.Data == "br" || node.Data == "hr" {
+			return false
+		}
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if !isBlank(child) {
+			return false
+		}
+	}
+	return true
+}
+
+func bodyAttr(node *html.Node, name string) (string, bool) {
+	for _, attr := range node.Attr {
+		if attr.Namespace == "" && strings.EqualFold(attr.Key, name) {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+// base 기준으로 주소를 풀고 schemes에 있는 주소만 통과시킨다.
+func absoluteUrl(base *url.URL, value string, schemes ...string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", false
+	}
+	parsed, err := base.Parse(value)
+	if err != nil {
+		return "", false
+	}
+	for _, scheme := range schemes {
+		if parsed.Scheme == scheme {
+			return parsed.String(), true
+		}
+	}
+	return "", false
+}
+
+var markdownBlockTags = map[string]bool{
+	"p": true, "div": true, "hr": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "li": true, "blockquote": true, "pre": true, "table": true,
+}
+
+var (
+	markdownSpaces  = regexp.MustCompile(`[ \t\r\n\f\x{00a0}]+`)
+	markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`)
+	markdownUrl     = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29")
+)
+
+// 정리한 본문을 Markdown으로 쓴다. 블록 사이는 빈 줄로 나눈다.
+func renderMarkdown(root *html.Node) string {
+	if root == nil {
+		return ""
+	}
+	return strings.Join(markdownBlocks(root), "\n\n")
+}
+
+// parent의 자식을 블록 단위로 나눈다. 블록 태그 사이의 글자와 인라인 태그는 한 문단으로 모은다.
+func markdownBlocks(parent *html.Node) []string {
+	blocks := make([]string, 0)
+	var paragraph strings.Builder
+	flush := func() {
+		if text := markdownLines(paragraph.String()); text != "" {
+			blocks = append(blocks, text)
+		}
+		paragraph.Reset()
+	}
+	for node := parent.FirstChild; node != nil; node = node.NextSibling {
+		if node.Type != html.ElementNode || !markdownBlockTags[node.Data] {
+			paragraph.WriteString(markdownInline(node))
+			continue
+		}
+		flush()
+		blocks = append(blocks, markdownBlock(node)...)
+	}
+	flush()
+	return blocks
+}
+
+func markdownBlock(node *html.Node) []string {
+	switch node.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := markdownLines(markdownChildren(node))
+		if text == "" {
+			return nil
+		}
+		level := int(node.Data[1] - '0')
+		return []string{strings.Repeat("#", level) + " " + strings.ReplaceAll(text, "  \n", " ")}
+	case "hr":
+		return []string{"---"}
+	case "ul", "ol":
+		list := markdownList(node)
+		if list == "" {
+			return nil
+		}
+		return []string{list}
+	case "blockquote":
+		inner := strings.Join(markdownBlocks(node), "\n\n")
+		if inner == "" {
+			return nil
+		}
+		lines := strings.Split(inner, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return []string{strings.Join(lines, "\n")}
+	case "pre":
+		text := strings.Trim(textContent(node), "\n")
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		return []string{"```\n" + text + "\n```"}
+	case "table":
+		return markdownTable(node)
+	default:
+		return markdownBlocks(node)
+	}
+}
+
+// 항목 안의 블록은 줄바꿈으로 잇고, 둘째 줄부터는 표시 너비만큼 들여 쓴다.
+func markdownList(list *html.Node) string {
+	items := make([]string, 0)
+	number := 1
+	for item := list.FirstChild; item != nil; item = item.NextSibling {
+		if item.Type != html.ElementNode || item.Data != "li" {
+			continue
+		}
+		marker := "- "
+		if list.Data == "ol" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		text := strings.Join(markdownBlocks(item), "\n")
+		if text == "" {
+			continue
+		}
+		items = append(items, marker+strings.ReplaceAll(text, "\n", "\n"+strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, "\n")
+}
+
+func markdownInline(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return markdownEscaper.Replace(markdownSpaces.ReplaceAllString(node.Data, " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch node.Data {
+	case "br":
+		return "\n"
+	case "strong":
+		return markdownWrap("**", markdownChildren(node))
+	case "em":
+		return markdownWrap("*", markdownChildren(node))
+	case "s":
+		return markdownWrap("~~", markdownChildren(node))
+	case "code":
+		return markdownWrap("`", markdownSpaces.ReplaceAllString(textContent(node), " "))
+	case "a":
+		href, _ := bodyAttr(node, "href")
+		text := strings.TrimSpace(markdownChildren(node))
+		if text == "" {
+			text = markdownEscaper.Replace(href)
+		}
+		return "[" + text + "](" + markdownUrl.Replace(href) + ")"
+	case "img":
+		src, _ := bodyAttr(node, "src")
+		alt, _ := bodyAttr(node, "alt")
+		return "![" + markdownEscaper.Replace(strings.TrimSpace(alt)) + "](" + markdownUrl.Replace(src) + ")"
+	}
+	if markdownBlockTags[node.Data] {
+		return "\n" + strings.Join(markdownBlocks(node), "\n") + "\n"
+	}
+	return markdownChildren(node)
+}
+
+func markdownChildren(node *html.Node) string {
+	var builder strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		builder.WriteString(markdownInline(child))
+	}
+	return builder.String()
+}
+
+// 강조 표시는 앞뒤 공백 밖에 둔다. 내용이 비어 있으면 표시하지 않는다.
+func markdownWrap(mark, text string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	start := strings.Index(text, trimmed)
+	return text[:start] + mark + trimmed + mark + text[start+len(trimmed):]
+}
+
+// 줄마다 앞뒤 공백을 지우고 빈 줄을 뺀 뒤 Markdown 줄바꿈(공백 두 개)으로 잇는다.
+func markdownLines(text string) string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "  \n")
+}
+
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+	var builder strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		builder.WriteString(textContent(child))
+	}
+	return builder.String()
+}
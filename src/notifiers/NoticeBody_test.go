@@ -0,0 +1,53 @@
+package notifiers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestGetBodySanitizesAndConvertsToMarkdown(t *testing.T) {
+	notifier := newTestNotifier("")
+	notifier.Template.BodySelector = "#body"
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<div id="body" class="view">
+<p style="color:red" onclick="alert(1)">안내 <b>필독</b>&nbsp;사항</p>
+<script>alert(1)</script>
+<ul><li>첫째 <a href="/apply.do?id=1" target="_blank">신청</a></li><li><i>둘째</i></li></ul>
+본문 밖 글자<br>다음 줄
+<p><a href="javascript:alert(1)">눌러 보기</a> <img src="poster.png" onerror="alert(1)" alt="포스터"></p>
+<p>&nbsp;</p>
+</div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := notifier.getBody(doc, "https://www.ajou.ac.kr/kr/notice.do?articleNo=1")
+	body, markdown := renderHTML(root), renderMarkdown(root)
+	wantBody := `<p>안내 <strong>필독</strong> 사항</p>
+<ul><li>첫째 <a href="https://www.ajou.ac.kr/apply.do?id=1">신청</a></li><li><em>둘째</em></li></ul>
+본문 밖 글자<br/>다음 줄
+<p>눌러 보기 <img src="https://www.ajou.ac.kr/kr/poster.png" alt="포스터"/></p>`
+	if body != wantBody {
+		t.Errorf("body = %q, want %q", body, wantBody)
+	}
+	wantMarkdown := "안내 **필독** 사항\n\n" +
+		"- 첫째 [신청](https://www.ajou.ac.kr/apply.do?id=1)\n- *둘째*\n\n" +
+		"본문 밖 글자  \n다음 줄\n\n" +
+		"눌러 보기 ![포스터](https://www.ajou.ac.kr/kr/poster.png)"
+	if markdown != wantMarkdown {
+		t.Errorf("markdown = %q, want %q", markdown, wantMarkdown)
+	}
+}
+
+func TestGetBodyWithoutSelector(t *testing.T) {
+	notifier := newTestNotifier("")
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<p>본문</p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := notifier.getBody(doc, "https://www.ajou.ac.kr/")
+	if root != nil || renderHTML(root) != "" || renderMarkdown(root) != "" || len(getTables(root)) != 0 {
+		t.Errorf("getBody() = %v, want nil", root)
+	}
+}
@@ -0,0 +1,47 @@
+package notifiers
+
+import (
+	"strings"
+	"time"
+
+	. "Notifier/models"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 한국은 서머타임이 없어 고정 오프셋으로 충분하다. tzdata가 없는 컨테이너에서도 동작한다.
+var seoul = time.FixedZone("Asia/Seoul", 9*60*60)
+
+// 템플릿의 DateLayouts를 차례로 시도해 게시판 날짜를 RFC3339로 바꾼다. 읽지 못하면 빈 문자열을 반환한다.
+func (notifier *BaseNotifier) parseDate(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	for _, layout := range notifier.Template.DateLayouts {
+		date, err := time.ParseInLocation(layout, value, seoul)
+		if err == nil {
+			return date.Format(time.RFC3339)
+		}
+	}
+	return ""
+}
+
+// 상세 페이지에 날짜가 있으면 목록의 날짜보다 우선한다. 게시일을 읽지 못하면 크롤링한 시각을 쓴다.
+func (notifier *BaseNotifier) setDetailDates(doc *goquery.Document, notice *Notice, crawledAt time.Time) {
+	fields := notifier.Template.DetailFields
+	if rule, ok := fields["date"]; ok {
+		if date := notifier.parseDate(notifier.getField(doc.Selection, rule)); date != "" {
+			notice.Date = date
+		}
+	}
+	if rule, ok := fields["modifiedDate"]; ok {
+		if date := notifier.parseDate(notifier.getField(doc.Selection, rule)); date != "" {
+			notice.ModifiedDate = date
+		}
+	}
+
+	notice.CrawledAt = crawledAt.In(seoul).Format(time.RFC3339)
+	if notice.Date == "" {
+		notice.Date = notice.CrawledAt
+	}
+}
@@ -0,0 +1,44 @@
+package notifiers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "Notifier/models"
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestParseDate(t *testing.T) {
+	notifier := newTestNotifier("")
+	notifier.Template.DateLayouts = []string{"2006.01.02 15:04", "06.01.02"}
+
+	tests := map[string]string{
+		"2024.08.01 09:12": "2024-08-01T09:12:00+09:00",
+		" 24.08.01\n":      "2024-08-01T00:00:00+09:00",
+		"2024-08-01":       "",
+		"":                 "",
+	}
+	for value, want := range tests {
+		if got := notifier.parseDate(value); got != want {
+			t.Errorf("parseDate(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+// 게시일을 읽지 못한 공지는 크롤링한 시각을 게시일로 쓴다.
+func TestSetDetailDatesFallsBackToCrawlTime(t *testing.T) {
+	notifier := newTestNotifier("")
+	notifier.Template.DateLayouts = []string{"2006-01-02"}
+	notifier.Template.DetailFields = map[string]FieldRule{"date": {Selector: "span.date"}}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<span class="date">어제</span>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notice := Notice{}
+	notifier.setDetailDates(doc, &notice, time.Date(2024, 9, 1, 15, 30, 0, 0, time.UTC))
+	if notice.Date != "2024-09-02T00:30:00+09:00" || notice.CrawledAt != notice.Date || notice.ModifiedDate != "" {
+		t.Errorf("notice dates = (%q, %q, %q), want the crawl time in Seoul", notice.Date, notice.ModifiedDate, notice.CrawledAt)
+	}
+}
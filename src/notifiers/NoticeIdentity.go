@@ -0,0 +1,55 @@
+package notifiers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+
+	. "Notifier/models"
+)
+
+// 상세 페이지 URL의 게시글 번호로 공지를 식별한다. 번호를 찾을 수 없으면 URL 전체를 식별자로 쓴다.
+func (notifier *BaseNotifier) getArticleId(noticeUrl string) string {
+	param := notifier.Template.ArticleIdParam
+	if param == "" {
+		return noticeUrl
+	}
+	parsed, err := url.Parse(noticeUrl)
+	if err != nil {
+		return noticeUrl
+	}
+	articleId := parsed.Query().Get(param)
+	if articleId == "" {
+		return noticeUrl
+	}
+	return articleId
+}
+
+// 목록 행의 게시글 번호와 지문. 전송 기록은 둘을 함께 비교한다.
+func (notifier *BaseNotifier) rowIdentity(rowNotice Notice) (string, string) {
+	return notifier.getArticleId(rowNotice.Url), noticeFingerprint(rowNotice)
+}
+
+// 목록 행의 게시일과 작성 부서로 글의 지문을 만든다. 제목처럼 고쳐질 수 있는 필드는 넣지 않는다.
+// 목록에 둘 다 없으면 빈 문자열이다.
+func noticeFingerprint(rowNotice Notice) string {
+	if rowNotice.Date == "" && rowNotice.Department == "" {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(rowNotice.Date + "\x00" + rowNotice.Department))
+	return hex.EncodeToString(hash[:])
+}
+
+// 수정 여부를 비교하는 제목, 본문, 이미지로 내용의 지문을 만든다.
+func contentFingerprint(notice Notice) string {
+	fields := append([]string{notice.Title, notice.Content}, notice.Images...)
+	hash := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	return hex.EncodeToString(hash[:])
+}
+
+// 고정 해제, 삭제 이벤트는 내용을 비교하지 않으므로 이벤트 이름과 게시글 번호로 지문을 만든다.
+func eventFingerprint(event, articleId string) string {
+	hash := sha256.Sum256([]byte(event + "\x00" + articleId))
+	return hex.EncodeToString(hash[:])
+}
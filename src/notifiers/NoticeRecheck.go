@@ -0,0 +1,146 @@
+package notifiers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	. "Notifier/models"
+	. "Notifier/src/store"
+	. "Notifier/src/utils"
+)
+
+// 최근에 보낸 공지의 상세 페이지를 다시 읽어 제목, 본문, 이미지가 바뀌었으면 updated 이벤트를 notice_outbox에 넣는다.
+// since 이후에 보낸 공지 중 최근 것부터 limit개까지만 확인한다.
+func (notifier *BaseNotifier) Recheck(ctx context.Context, since time.Time, limit int) {
+	if notifier.isPaused() {
+		return
+	}
+	if !notifier.tryStartRecheck() {
+		Logger.Warn("Skipped recheck, previous recheck still in progress", "topic", notifier.EnglishTopic, "type", notifier.Type)
+		return
+	}
+	defer notifier.finishRecheck()
+
+	logger := Logger.With("run_id", NewRunId(), "topic", notifier.EnglishTopic, "type", notifier.Type)
+	ctx = WithLogger(ctx, logger)
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Recheck panicked", "error", fmt.Errorf("%v", r))
+		}
+	}()
+
+	events, err := notifier.recheck(ctx, since, limit)
+	if err != nil {
+		logger.Error("Recheck failed", "error", err)
+		return
+	}
+	logger.Debug("Recheck finished", "events", len(events))
+}
+
+func (notifier *BaseNotifier) recheck(ctx context.Context, since time.Time, limit int) ([]NoticeEvent, error) {
+	logger := LoggerFrom(ctx)
+	archived, err := Store.LoadArchivedNotices([]string{notifier.EnglishTopic}, limit)
+	if err != nil {
+		return nil, err
+	}
+	delivered := make(map[string]Notice, len(archived))
+	for _, notice := range archived {
+		if notice.DeliveredAt.After(since) {
+			delivered[notice.ArticleId] = notice.Notice
+		}
+	}
+	if len(delivered) == 0 {
+		return nil, nil
+	}
+
+	// 제목은 목록에만 있으므로 목록 페이지도 다시 읽는다. 목록에서 내려간 공지는 보관된 제목과 비교한다.
+	rows, err := notifier.listRows(ctx)
+	if err != nil {
+		logger.Warn("Failed to load list page for recheck", "url", notifier.NoticeUrl, "error", err)
+	}
+
+	noticeChan := make(chan Notice, len(delivered))
+	for articleId, notice := range delivered {
+		if row, ok := rows[articleId]; ok {
+			notice.Title = row.Title
+			notice.Category = row.Category
+			notice.Department = row.Department
+		}
+		go notifier.getNotice(ctx, notice, noticeChan)
+	}
+
+	events := make([]NoticeEvent, 0)
+	for range delivered {
+		notice := <-noticeChan
+		if notice.Url == "" {
+			continue
+		}
+		// 상세 페이지가 비어 있으면 글이 바뀐 것보다 페이지를 잘못 읽었을 가능성이 크다.
+		if notice.Content == "" && len(notice.Images) == 0 {
+			logger.Warn("Notice page has no content, skipping recheck", "notice_id", notice.ID, "url", notice.Url)
+			continue
+		}
+		articleId := notifier.getArticleId(notice.Url)
+		changes := noticeChanges(delivered[articleId], notice)
+		if len(changes) == 0 {
+			continue
+		}
+
+		event := NoticeEvent{Event: EventUpdated, Notice: notice, Changes: changes}
+		err = notifier.enqueueEvent(articleId, contentFingerprint(notice), event)
+		if err != nil {
+			logger.Error("Failed to enqueue notice event", "event", event.Event, "notice_id", notice.ID, "url", notice.Url, "error", err)
+			continue
+		}
+		logger.Info("Notice event queued", "event", event.Event, "notice_id", notice.ID, "url", notice.Url, "title", notice.Title)
+		NoticeEvents.WithLabelValues(notifier.EnglishTopic, notifier.typeLabel(), event.Event).Inc()
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// 목록 페이지의 box/num 행을 게시글 번호로 찾을 수 있게 읽는다. 조건부 GET을 쓰지 않는다.
+func (notifier *BaseNotifier) listRows(ctx context.Context) (map[string]Notice, error) {
+	doc, err := notifier.fetchDocument(ctx, notifier.NoticeUrl, "recheck")
+	if err != nil {
+		return nil, err
+	}
+	rows := notifier.rowsByArticleId(doc.Find(notifier.Template.BoxNoticeSelector))
+	maps.Copy(rows, notifier.rowsByArticleId(doc.Find(notifier.Template.NumNoticeSelector)))
+	return rows, nil
+}
+
+// sink마다 이벤트 항목을 하나씩 넣는다. 같은 내용의 이벤트가 아직 남아 있으면 다시 넣지 않는다.
+func (notifier *BaseNotifier) enqueueEvent(articleId, fingerprint string, event NoticeEvent) error {
+	entries := make([]OutboxEntry, 0, len(notifier.Sinks))
+	for _, sink := range notifier.Sinks {
+		entries = append(entries, OutboxEntry{
+			Topic:       notifier.EnglishTopic,
+			Kind:        event.Event,
+			ArticleId:   articleId,
+			Sink:        sink,
+			Fingerprint: fingerprint,
+			Notice:      event.Notice,
+			Changes:     event.Changes,
+		})
+	}
+	return Store.EnqueueNotices(entries)
+}
+
+// 보낸 공지와 다시 읽은 공지의 제목, 본문, 이미지를 비교한다.
+func noticeChanges(old, new Notice) []FieldChange {
+	changes := make([]FieldChange, 0)
+	if old.Title != new.Title {
+		changes = append(changes, FieldChange{Field: "title", Old: old.Title, New: new.Title})
+	}
+	if old.Content != new.Content {
+		changes = append(changes, FieldChange{Field: "content", Old: old.Content, New: new.Content})
+	}
+	if !slices.Equal(old.Images, new.Images) {
+		changes = append(changes, FieldChange{Field: "images", Old: old.Images, New: new.Images})
+	}
+	return changes
+}
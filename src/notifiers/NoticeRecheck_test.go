@@ -0,0 +1,88 @@
+package notifiers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "Notifier/models"
+	. "Notifier/src/store"
+)
+
+func TestRecheckQueuesUpdatedNotices(t *testing.T) {
+	Store = MemoryStore{}.New()
+	template := loadFixtureTemplate(t, "ajou-cms")
+	server := newFixtureServer(filepath.Join("testdata", "ajou-cms"), template.ArticleIdParam)
+	defer server.Close()
+	notifier := newFixtureNotifier(t, server.URL+"/kr/ajou/notice.do", template)
+
+	// 보낸 뒤에 제목과 본문이 바뀐 공지 하나를 포함해 보관함을 채운다.
+	for _, scraped := range scrapeFixture(t, notifier) {
+		notice := scraped.Notice
+		if scraped.ArticleId == "334470" {
+			notice.Title = "이전 제목"
+			notice.Content = "이전 본문"
+		}
+		err := Store.EnqueueNotices([]OutboxEntry{{Topic: notifier.EnglishTopic, Kind: "num", ArticleId: scraped.ArticleId, Sink: "backend", Notice: notice}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	due, _ := Store.LoadDueOutboxEntries(10)
+	for _, entry := range due {
+		Store.MarkOutboxSent(entry)
+	}
+
+	ctx := context.Background()
+	events, err := notifier.recheck(ctx, time.Now().Add(time.Hour), 10)
+	if err != nil || len(events) != 0 {
+		t.Fatalf("recheck of notices sent before since = (%+v, %v), want nothing", events, err)
+	}
+
+	for run := 1; run <= 2; run++ {
+		events, err = notifier.recheck(ctx, time.Now().Add(-time.Hour), 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 1 || events[0].Event != EventUpdated || len(events[0].Changes) != 2 {
+			t.Fatalf("run %d events = %+v, want one update with title and content changes", run, events)
+		}
+		changes := events[0].Changes
+		if changes[0].Field != "title" || changes[0].Old != "이전 제목" || changes[0].New != events[0].Notice.Title || changes[1].Field != "content" {
+			t.Errorf("changes = %+v", changes)
+		}
+	}
+
+	// 아직 보내지 않은 같은 내용의 이벤트는 다시 넣지 않는다.
+	due, _ = Store.LoadDueOutboxEntries(10)
+	if len(due) != 1 || due[0].Kind != EventUpdated || due[0].ArticleId != "334470" || due[0].Sink != "backend" {
+		t.Fatalf("due entries = %+v, want one updated event", due)
+	}
+
+	Store.MarkOutboxSent(due[0])
+	events, err = notifier.recheck(ctx, time.Now().Add(-time.Hour), 10)
+	if err != nil || len(events) != 0 {
+		t.Errorf("recheck after the update was sent = (%+v, %v), want nothing", events, err)
+	}
+}
+
+// 수정 확인이 오래 걸려도 크롤링은 건너뛰지 않고, 수정 확인끼리만 겹치지 않는다.
+func TestRecheckDoesNotBlockCrawls(t *testing.T) {
+	notifier := newTestNotifier("")
+	if !notifier.tryStartRecheck() {
+		t.Fatal("first recheck did not start")
+	}
+	if !notifier.tryStart() {
+		t.Fatal("crawl was blocked by a running recheck")
+	}
+	notifier.finish()
+	if notifier.tryStartRecheck() {
+		t.Error("second recheck started while the first is running")
+	}
+	notifier.finishRecheck()
+
+	if status := notifier.Status(); status.SkippedRuns != 0 || status.Running {
+		t.Errorf("status = %+v, want no skipped or running crawls", status)
+	}
+}
@@ -0,0 +1,254 @@
+package notifiers
+
+import (
+	"strconv"
+	"strings"
+
+	. "Notifier/models"
+	"golang.org/x/net/html"
+)
+
+// 표를 칸 단위 격자로 편다. rowspan, colspan으로 합친 칸은 차지하는 자리마다 같은 노드가 들어가고,
+// 칸이 모자란 행의 빈자리는 nil이다. headers는 머리글로 쓸 앞쪽 행 수로, thead에 있거나 th로만 된 행이다.
+type tableGrid struct {
+	caption *html.Node
+	cells   [][]*html.Node
+	headers int
+}
+
+func newTableGrid(table *html.Node) tableGrid {
+	grid := tableGrid{}
+	rows := make([]*html.Node, 0)
+	headerRows := make([]bool, 0)
+	var visit func(node *html.Node, inHead bool)
+	visit = func(node *html.Node, inHead bool) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			switch child.Data {
+			case "caption":
+				if grid.caption == nil {
+					grid.caption = child
+				}
+			case "thead", "tbody", "tfoot":
+				visit(child, child.Data == "thead")
+			case "tr":
+				rows = append(rows, child)
+				headerRows = append(headerRows, inHead || onlyHeaderCells(child))
+			}
+		}
+	}
+	visit(table, false)
+
+	// 위 행의 rowspan이 차지한 자리는 건너뛰고 왼쪽부터 채운다. rowspan은 표의 마지막 행까지만 센다.
+	grid.cells = make([][]*html.Node, len(rows))
+	for r, row := range rows {
+		column := 0
+		for cell := row.FirstChild; cell != nil; cell = cell.NextSibling {
+			if cell.Type != html.ElementNode || (cell.Data != "td" && cell.Data != "th") {
+				continue
+			}
+			for column < len(grid.cells[r]) && grid.cells[r][column] != nil {
+				column++
+			}
+			rowspan, colspan := cellSpan(cell, "rowspan"), cellSpan(cell, "colspan")
+			for i := r; i < r+rowspan && i < len(rows); i++ {
+				for j := column; j < column+colspan; j++ {
+					for len(grid.cells[i]) <= j {
+						grid.cells[i] = append(grid.cells[i], nil)
+					}
+					grid.cells[i][j] = cell
+				}
+			}
+			column += colspan
+		}
+	}
+
+	width := 0
+	for _, row := range grid.cells {
+		width = max(width, len(row))
+	}
+	for r := range grid.cells {
+		for len(grid.cells[r]) < width {
+			grid.cells[r] = append(grid.cells[r], nil)
+		}
+	}
+	for grid.headers < len(headerRows) && headerRows[grid.headers] {
+		grid.headers++
+	}
+	return grid
+}
+
+func onlyHeaderCells(row *html.Node) bool {
+	found := false
+	for cell := row.FirstChild; cell != nil; cell = cell.NextSibling {
+		if cell.Type != html.ElementNode {
+			continue
+		}
+		if cell.Data == "td" {
+			return false
+		}
+		found = found || cell.Data == "th"
+	}
+	return found
+}
+
+// sanitize에서 1 초과 1000 이하로 걸러 낸 값이다. 없으면 1이다.
+func cellSpan(cell *html.Node, name string) int {
+	value, _ := bodyAttr(cell, name)
+	span, err := strconv.Atoi(value)
+	if err != nil || span < 1 {
+		return 1
+	}
+	return span
+}
+
+// 칸이 하나뿐인 표는 글을 감싸는 상자로 보고 표로 다루지 않는다.
+func (grid tableGrid) isLayout() bool {
+	return len(grid.cells) == 0 || len(grid.cells[0]) == 0 || (len(grid.cells) == 1 && len(grid.cells[0]) == 1)
+}
+
+// 머리글 행을 열마다 한 줄로 합치고 나머지 행을 돌려준다. 머리글 행이 없으면 첫 행을 머리글로 쓴다.
+// 같은 칸이 머리글 여러 줄에 걸쳐 있으면 한 번만 쓴다.
+func (grid tableGrid) split(text func(cell *html.Node) string) ([]string, [][]string) {
+	headers := max(grid.headers, 1)
+	header := make([]string, len(grid.cells[0]))
+	for column := range header {
+		parts := make([]string, 0, headers)
+		for r := 0; r < headers; r++ {
+			cell := grid.cells[r][column]
+			if cell == nil || (r > 0 && cell == grid.cells[r-1][column]) {
+				continue
+			}
+			if part := text(cell); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		header[column] = strings.Join(parts, " ")
+	}
+
+	rows := make([][]string, 0, len(grid.cells)-headers)
+	for _, cells := range grid.cells[headers:] {
+		row := make([]string, len(cells))
+		empty := true
+		for column, cell := range cells {
+			if cell != nil {
+				row[column] = text(cell)
+			}
+			empty = empty && row[column] == ""
+		}
+		if !empty {
+			rows = append(rows, row)
+		}
+	}
+	return header, rows
+}
+
+// 정리한 본문에서 표를 찾아 구조화한다. 표 안의 표는 바깥 칸의 글자로만 남긴다.
+func getTables(root *html.Node) []NoticeTable {
+	tables := make([]NoticeTable, 0)
+	if root == nil {
+		return tables
+	}
+	var visit func(node *html.Node)
+	visit = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			if child.Data != "table" {
+				visit(child)
+				continue
+			}
+			grid := newTableGrid(child)
+			if grid.isLayout() {
+				visit(child)
+				continue
+			}
+			table := NoticeTable{}
+			if grid.caption != nil {
+				table.Caption = plainText(grid.caption)
+			}
+			table.Header, table.Rows = grid.split(plainText)
+			tables = append(tables, table)
+		}
+	}
+	visit(root)
+	return tables
+}
+
+// 표를 GFM 표로 쓴다. 칸 안의 줄바꿈은 공백으로 바꾸고 | 는 이스케이프한다. 캡션은 표 앞 문단으로 쓴다.
+func markdownTable(table *html.Node) []string {
+	grid := newTableGrid(table)
+	if grid.isLayout() {
+		if len(grid.cells) == 0 || len(grid.cells[0]) == 0 {
+			return nil
+		}
+		return markdownBlocks(grid.cells[0][0])
+	}
+
+	header, rows := grid.split(markdownCell)
+	lines := make([]string, 0, len(rows)+2)
+	lines = append(lines, markdownTableRow(header))
+	lines = append(lines, strings.Repeat("| --- ", len(header))+"|")
+	for _, row := range rows {
+		lines = append(lines, markdownTableRow(row))
+	}
+
+	blocks := make([]string, 0, 2)
+	if grid.caption != nil {
+		if caption := markdownLines(markdownChildren(grid.caption)); caption != "" {
+			blocks = append(blocks, strings.ReplaceAll(caption, "  \n", " "))
+		}
+	}
+	return append(blocks, strings.Join(lines, "\n"))
+}
+
+// 표를 한 줄에 한 행씩 GFM 표로 쓴다. 캡션이 있으면 표 앞 줄에 쓴다. 평문 본문(Content)에 쓴다.
+func textTable(grid tableGrid, text func(cell *html.Node) string) []string {
+	header, rows := grid.split(text)
+	lines := make([]string, 0, len(rows)+3)
+	if grid.caption != nil {
+		if caption := text(grid.caption); caption != "" {
+			lines = append(lines, caption)
+		}
+	}
+	lines = append(lines, markdownTableRow(header))
+	lines = append(lines, strings.Repeat("| --- ", len(header))+"|")
+	for _, row := range rows {
+		lines = append(lines, markdownTableRow(row))
+	}
+	return lines
+}
+
+func markdownTableRow(cells []string) string {
+	return "| " + strings.Join(cells, " | ") + " |"
+}
+
+func markdownCell(cell *html.Node) string {
+	text := strings.ReplaceAll(markdownLines(markdownChildren(cell)), "  \n", " ")
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// 칸과 캡션의 글자. 줄바꿈과 블록 경계는 공백 하나로 바꾼다.
+func plainText(node *html.Node) string {
+	var builder strings.Builder
+	var visit func(node *html.Node)
+	visit = func(node *html.Node) {
+		switch {
+		case node.Type == html.TextNode:
+			builder.WriteString(node.Data)
+		case node.Type == html.ElementNode && (node.Data == "br" || markdownBlockTags[node.Data]):
+			builder.WriteString(" ")
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			visit(child)
+		}
+		if node.Type == html.ElementNode && markdownBlockTags[node.Data] {
+			builder.WriteString(" ")
+		}
+	}
+	visit(node)
+	return strings.TrimSpace(markdownSpaces.ReplaceAllString(builder.String(), " "))
+}
@@ -0,0 +1,41 @@
+package notifiers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "Notifier/models"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 모자란 칸은 빈 값으로 채우고, 표 밖으로 넘치는 rowspan은 마지막 행에서 자른다.
+func TestGetTablesExpandsSpans(t *testing.T) {
+	notifier := newTestNotifier("")
+	notifier.Template.BodySelector = "#body"
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<div id="body"><table>
+<tr><th>학년</th><th>대상 | 인원</th><th colspan="2">기간</th></tr>
+<tr><td rowspan="9">1</td><td>전체</td><td>3월</td></tr>
+<tr><td colspan="2"><b>미정</b></td><td>4월</td></tr>
+</table></div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := notifier.getBody(doc, "https://www.ajou.ac.kr/")
+	want := []NoticeTable{{
+		Header: []string{"학년", "대상 | 인원", "기간", "기간"},
+		Rows:   [][]string{{"1", "전체", "3월", ""}, {"1", "미정", "미정", "4월"}},
+	}}
+	if tables := getTables(root); !reflect.DeepEqual(tables, want) {
+		t.Errorf("tables = %+v, want %+v", tables, want)
+	}
+
+	wantMarkdown := "| 학년 | 대상 \\| 인원 | 기간 | 기간 |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| 1 | 전체 | 3월 |  |\n" +
+		"| 1 | **미정** | **미정** | 4월 |"
+	if markdown := renderMarkdown(root); markdown != wantMarkdown {
+		t.Errorf("markdown = %q, want %q", markdown, wantMarkdown)
+	}
+}
@@ -1,5 +1,18 @@
 package notifiers
 
+import (
+	"context"
+	"time"
+
+	. "Notifier/models"
+)
+
 type Notifier interface {
-	Notify()
+	Notify(ctx context.Context)
+	Recheck(ctx context.Context, since time.Time, limit int)
+	Topic() string
+	Status() NotifierStatus
+	Pause()
+	Resume()
+	Delivered(notice Notice)
 }
@@ -0,0 +1,135 @@
+package notifiers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	. "Notifier/models"
+	. "Notifier/src/sinks"
+	. "Notifier/src/store"
+	. "Notifier/src/utils"
+)
+
+// notice_outbox에 쌓인 공지를 항목의 sink로 보내고, 실패하면 지수 백오프로 재시도한다.
+// MaxAttempts번 실패한 공지는 dead 상태로 남겨 더 이상 보내지 않는다.
+type OutboxWorker struct {
+	BatchSize   int
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	SendTimeout time.Duration
+	sinks       map[string]Sink
+	notifiers   map[string]Notifier
+}
+
+func (OutboxWorker) New(sinks map[string]Sink, notifiers []Notifier) *OutboxWorker {
+	notifiersByTopic := make(map[string]Notifier, len(notifiers))
+	for _, notifier := range notifiers {
+		notifiersByTopic[notifier.Topic()] = notifier
+	}
+
+	return &OutboxWorker{
+		BatchSize:   100,
+		MaxAttempts: 10,
+		BaseDelay:   30 * time.Second,
+		MaxDelay:    time.Hour,
+		SendTimeout: 30 * time.Second,
+		sinks:       sinks,
+		notifiers:   notifiersByTopic,
+	}
+}
+
+// ctx가 취소되면 진행 중인 전송까지만 마치고 반환한다.
+func (worker *OutboxWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			worker.Deliver(ctx)
+		}
+	}
+}
+
+// 재시도 시각이 된 공지를 한 번씩 전송한다.
+func (worker *OutboxWorker) Deliver(ctx context.Context) {
+	entries, err := Store.LoadDueOutboxEntries(worker.BatchSize)
+	if err != nil {
+		Logger.Error("Failed to load outbox", "error", err)
+	}
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+		worker.deliver(ctx, entry)
+	}
+}
+
+// 종료 신호를 받아도 이미 시작한 전송은 SendTimeout 안에서 끝까지 진행한다.
+func (worker *OutboxWorker) deliver(ctx context.Context, entry OutboxEntry) {
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), worker.SendTimeout)
+	defer cancel()
+
+	notifier := worker.notifiers[entry.Topic]
+	typeLabel := ""
+	if notifier != nil {
+		typeLabel = strconv.Itoa(notifier.Status().Type)
+	}
+
+	logger := Logger.With("topic", entry.Topic, "type", typeLabel, "sink", entry.Sink, "kind", entry.Kind, "outbox_id", entry.ID, "notice_id", entry.Notice.ID, "url", entry.Notice.Url)
+	sink, ok := worker.sinks[entry.Sink]
+	var err error
+	if ok {
+		startedAt := time.Now()
+		if IsEventKind(entry.Kind) {
+			err = sink.SendEvent(sendCtx, NoticeEvent{Event: entry.Kind, Notice: entry.Notice, Changes: entry.Changes})
+		} else {
+			err = sink.Send(sendCtx, entry.Notice)
+		}
+		WebhookDuration.WithLabelValues(entry.Topic, typeLabel, entry.Sink).Observe(time.Since(startedAt).Seconds())
+	} else {
+		// config/sinks.json에서 지워진 sink는 다시 시도하지 않는다.
+		err = fmt.Errorf("unknown sink %q", entry.Sink)
+	}
+	if err != nil {
+		WebhookDeliveries.WithLabelValues(entry.Topic, typeLabel, entry.Sink, "failure").Inc()
+		attempts := entry.Attempts + 1
+		dead := attempts >= worker.MaxAttempts || !ok
+		if dead {
+			logger.Error("Giving up on notice", "attempts", attempts, "error", err)
+		} else {
+			logger.Warn("Failed to send notice", "attempts", attempts, "error", err)
+		}
+		err = Store.MarkOutboxFailed(entry, err, time.Now().Add(worker.backoff(attempts)), dead)
+		if err != nil {
+			logger.Error("Failed to update outbox entry", "error", err)
+		}
+		return
+	}
+
+	WebhookDeliveries.WithLabelValues(entry.Topic, typeLabel, entry.Sink, "success").Inc()
+
+	err = Store.MarkOutboxSent(entry)
+	if err != nil {
+		// 전송은 되었으므로 다음 시도에서 중복 전송될 수 있다.
+		logger.Error("Failed to mark outbox entry as sent", "error", err)
+		return
+	}
+	logger.Info("Notice sent", "title", entry.Notice.Title, "attempts", entry.Attempts+1)
+	if notifier != nil && !IsEventKind(entry.Kind) {
+		notifier.Delivered(entry.Notice)
+	}
+}
+
+func (worker *OutboxWorker) backoff(attempts int) time.Duration {
+	delay := worker.BaseDelay
+	for i := 1; i < attempts && delay < worker.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, worker.MaxDelay)
+}
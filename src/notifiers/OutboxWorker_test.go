@@ -0,0 +1,148 @@
+package notifiers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "Notifier/models"
+	. "Notifier/src/sinks"
+	. "Notifier/src/store"
+	_ "modernc.org/sqlite"
+)
+
+// 보낸 공지를 기록하고, err가 있으면 전송에 실패한다.
+type recordingSink struct {
+	name string
+	err  error
+	sent []string
+}
+
+func (sink *recordingSink) Name() string { return sink.name }
+
+func (sink *recordingSink) Send(_ context.Context, notice Notice) error {
+	sink.sent = append(sink.sent, notice.ID)
+	return sink.err
+}
+
+func (sink *recordingSink) SendEvent(_ context.Context, event NoticeEvent) error {
+	sink.sent = append(sink.sent, event.Notice.ID)
+	return sink.err
+}
+
+func newTestOutboxWorker(sinks ...*recordingSink) *OutboxWorker {
+	byName := make(map[string]Sink, len(sinks))
+	for _, sink := range sinks {
+		byName[sink.name] = sink
+	}
+	worker := OutboxWorker{}.New(byName, nil)
+	worker.BaseDelay = 0
+	return worker
+}
+
+func enqueueTestNotice(t *testing.T, sink, id string) {
+	t.Helper()
+	err := Store.EnqueueNotices([]OutboxEntry{{Topic: "Outbox", Kind: "num", ArticleId: id, Sink: sink, Notice: Notice{ID: id}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func dueCount(t *testing.T) int {
+	t.Helper()
+	due, err := Store.LoadDueOutboxEntries(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(due)
+}
+
+func TestOutboxWorkerBackoff(t *testing.T) {
+	worker := OutboxWorker{BaseDelay: 30 * time.Second, MaxDelay: 5 * time.Minute}
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, delay := range want {
+		if got := worker.backoff(i + 1); got != delay {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, delay)
+		}
+	}
+	if got := worker.backoff(100); got != 5*time.Minute {
+		t.Errorf("backoff(100) = %v, want MaxDelay", got)
+	}
+}
+
+// MaxAttempts번 실패한 공지는 dead가 되어 다시 보내지 않는다.
+func TestOutboxWorkerGivesUpAfterMaxAttempts(t *testing.T) {
+	Store = MemoryStore{}.New()
+	sink := &recordingSink{name: "failing", err: errors.New("boom")}
+	worker := newTestOutboxWorker(sink)
+	worker.MaxAttempts = 3
+	enqueueTestNotice(t, "failing", "1")
+
+	ctx := context.Background()
+	for attempt := 1; attempt <= 3; attempt++ {
+		if dueCount(t) != 1 {
+			t.Fatalf("attempt %d: notice is not due", attempt)
+		}
+		worker.Deliver(ctx)
+	}
+	worker.Deliver(ctx)
+
+	if len(sink.sent) != 3 || dueCount(t) != 0 {
+		t.Errorf("sink tried %d times with %d due entries left, want 3 tries and none left", len(sink.sent), dueCount(t))
+	}
+}
+
+// config/sinks.json에서 지워진 sink의 공지는 바로 dead가 되고 다른 sink의 공지는 보낸다.
+func TestOutboxWorkerDropsUnknownSink(t *testing.T) {
+	Store = MemoryStore{}.New()
+	sink := &recordingSink{name: "backend"}
+	worker := newTestOutboxWorker(sink)
+	enqueueTestNotice(t, "removed", "1")
+	enqueueTestNotice(t, "backend", "2")
+
+	worker.Deliver(context.Background())
+
+	if len(sink.sent) != 1 || sink.sent[0] != "2" || dueCount(t) != 0 {
+		t.Errorf("sent = %v with %d due entries left, want only 2 and none left", sink.sent, dueCount(t))
+	}
+}
+
+// 읽을 수 없는 항목은 dead가 되고 그 뒤에 넣은 공지는 그대로 보낸다.
+func TestOutboxWorkerSkipsUnreadableEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifier.db")
+	sqliteStore := SqliteStore{}.New(path)
+	defer sqliteStore.Close()
+	err := sqliteStore.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	Store = sqliteStore
+	sink := &recordingSink{name: "backend"}
+	worker := newTestOutboxWorker(sink)
+	enqueueTestNotice(t, "backend", "1")
+	enqueueTestNotice(t, "backend", "2")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	_, err = db.Exec("UPDATE notice_outbox SET payload = '{' WHERE article_id = '1'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worker.Deliver(context.Background())
+
+	if len(sink.sent) != 1 || sink.sent[0] != "2" || dueCount(t) != 0 {
+		t.Errorf("sent = %v with %d due entries left, want only 2 and none left", sink.sent, dueCount(t))
+	}
+	var status, lastError string
+	err = db.QueryRow("SELECT status, last_error FROM notice_outbox WHERE article_id = '1'").Scan(&status, &lastError)
+	if err != nil || status != OutboxDead || lastError == "" {
+		t.Errorf("unreadable entry = (%q, %q, %v), want dead with the decode error", status, lastError, err)
+	}
+}
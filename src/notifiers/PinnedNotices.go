@@ -0,0 +1,113 @@
+package notifiers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	. "Notifier/models"
+	. "Notifier/src/store"
+	. "Notifier/src/utils"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 지난 목록의 고정 공지 중 이번 목록에서 빠진 공지마다 unpinned 또는 deleted 이벤트를 넣는다.
+// 번호 목록에 있거나 상세 페이지가 열리면 고정만 풀린 것으로 보고, 상세 페이지가 404/410이거나 비어 있으면 삭제된 것으로 본다.
+// 상세 페이지를 읽지 못한 공지는 고정 공지로 남겨 다음 실행에서 다시 확인한다.
+// 고정 공지로 보낸 적이 없는 행(상세 페이지를 읽지 못해 대기열에 넣지 못한 행 등)은 기록하지 않는다.
+// 번호 목록으로 내려간 공지는 전송 기록에 남겨 새 번호 공지로 다시 보내지 않는다. scrapeNumNotice보다 먼저 불러야 한다.
+func (notifier *BaseNotifier) checkPinned(ctx context.Context, doc *goquery.Document) {
+	logger := LoggerFrom(ctx)
+	pinned := notifier.rowsByArticleId(doc.Find(notifier.Template.BoxNoticeSelector))
+	for articleId := range pinned {
+		if _, ok := notifier.seenNotices[SeenNoticeKey("box", articleId)]; !ok {
+			delete(pinned, articleId)
+		}
+	}
+	numRows := notifier.rowsByArticleId(doc.Find(notifier.Template.NumNoticeSelector))
+
+	for articleId, notice := range notifier.pinned {
+		if _, ok := pinned[articleId]; ok {
+			continue
+		}
+
+		event := EventUnpinned
+		if row, ok := numRows[articleId]; ok {
+			notice = row
+			err := notifier.markSeen("num", articleId, noticeFingerprint(row))
+			if err != nil {
+				logger.Error("Failed to save seen notice", "kind", "num", "article_id", articleId, "error", err)
+				pinned[articleId] = notice
+				continue
+			}
+		} else {
+			deleted, err := notifier.isDeleted(ctx, notice)
+			if err != nil {
+				logger.Warn("Failed to check removed pinned notice", "article_id", articleId, "url", notice.Url, "error", err)
+				pinned[articleId] = notice
+				continue
+			}
+			if deleted {
+				event = EventDeleted
+			}
+		}
+
+		// 보낸 이벤트는 대기열에서 지워지므로 고정과 해제를 반복해도 해제될 때마다 다시 들어간다.
+		err := notifier.enqueueEvent(articleId, eventFingerprint(event, articleId), NoticeEvent{Event: event, Notice: notice})
+		if err != nil {
+			logger.Error("Failed to enqueue notice event", "event", event, "article_id", articleId, "url", notice.Url, "error", err)
+			pinned[articleId] = notice
+			continue
+		}
+		logger.Info("Notice event queued", "event", event, "article_id", articleId, "url", notice.Url, "title", notice.Title)
+		NoticeEvents.WithLabelValues(notifier.EnglishTopic, notifier.typeLabel(), event).Inc()
+	}
+
+	if samePinned(pinned, notifier.pinned) {
+		return
+	}
+	err := Store.SavePinnedNotices(notifier.EnglishTopic, pinned)
+	if err != nil {
+		logger.Error("Failed to save pinned notices", "error", err)
+	}
+	notifier.pinned = pinned
+}
+
+// 목록 행을 게시글 번호로 찾을 수 있게 읽는다.
+func (notifier *BaseNotifier) rowsByArticleId(sels *goquery.Selection) map[string]Notice {
+	rows := make(map[string]Notice, sels.Length())
+	for i := range sels.Nodes {
+		rowNotice := notifier.getRowNotice(sels.Eq(i))
+		rows[notifier.getArticleId(rowNotice.Url)] = rowNotice
+	}
+	return rows
+}
+
+// 상세 페이지가 없거나 본문과 이미지가 모두 없으면 삭제된 공지로 본다.
+func (notifier *BaseNotifier) isDeleted(ctx context.Context, notice Notice) (bool, error) {
+	doc, err := notifier.fetchDocument(ctx, notice.Url, "detail")
+	var statusCodeErr *StatusCodeError
+	if errors.As(err, &statusCodeErr) {
+		code := statusCodeErr.StatusCode
+		if code == http.StatusNotFound || code == http.StatusGone {
+			return true, nil
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	empty := doc.Find(notifier.Template.ContentSelector).Nodes == nil && doc.Find(notifier.Template.ImagesSelector).Nodes == nil
+	return empty, nil
+}
+
+func samePinned(a, b map[string]Notice) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for articleId := range a {
+		if _, ok := b[articleId]; !ok {
+			return false
+		}
+	}
+	return true
+}
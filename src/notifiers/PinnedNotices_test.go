@@ -0,0 +1,156 @@
+package notifiers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "Notifier/models"
+	. "Notifier/src/store"
+)
+
+func TestCheckPinnedQueuesUnpinnedAndDeletedEvents(t *testing.T) {
+	Store = MemoryStore{}.New()
+	template := loadFixtureTemplate(t, "ajou-cms")
+	server := newFixtureServer(filepath.Join("testdata", "ajou-cms"), template.ArticleIdParam)
+	defer server.Close()
+	noticeUrl := server.URL + "/kr/ajou/notice.do"
+	notifier := newFixtureNotifier(t, noticeUrl, template)
+
+	// 334455는 계속 고정, 334470은 번호 공지로 내려가고, 999는 상세 페이지가 404다.
+	notifier.seenNotices["box:334455"] = ""
+	notifier.pinned = map[string]Notice{
+		"334455": {ID: "공지", Title: "고정", Url: noticeUrl + "?mode=view&articleNo=334455"},
+		"334470": {ID: "공지", Title: "고정이 풀린 공지", Url: noticeUrl + "?mode=view&articleNo=334470"},
+		"999":    {ID: "공지", Title: "삭제된 공지", Url: noticeUrl + "?mode=view&articleNo=999"},
+	}
+
+	ctx := context.Background()
+	doc, err := notifier.fetchDocument(ctx, noticeUrl, "recheck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for run := 1; run <= 2; run++ {
+		notifier.checkPinned(ctx, doc)
+	}
+
+	due, err := Store.LoadDueOutboxEntries(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := make(map[string]OutboxEntry, len(due))
+	for _, entry := range due {
+		events[entry.ArticleId] = entry
+	}
+	if len(due) != 2 || events["334470"].Kind != EventUnpinned || events["999"].Kind != EventDeleted {
+		t.Fatalf("due entries = %+v, want unpinned 334470 and deleted 999 once", due)
+	}
+	if events["334470"].Notice.ID != "1203" {
+		t.Errorf("unpinned notice = %+v, want the numbered row", events["334470"].Notice)
+	}
+
+	pinned, err := Store.LoadPinnedNotices(notifier.EnglishTopic)
+	if err != nil || len(pinned) != 1 || pinned["334455"].Url == "" {
+		t.Errorf("saved pinned notices = (%+v, %v), want only 334455", pinned, err)
+	}
+}
+
+// 번호 1190 행으로 내려간 고정 공지는 unpinned 이벤트로 한 번만 보내고 새 번호 공지로 보내지 않는다.
+func TestScrapeNoticeQueuesUnpinnedNoticeOnce(t *testing.T) {
+	Store = MemoryStore{}.New()
+	dir := t.TempDir()
+	writeShuffledList(t, dir)
+	detail, err := os.ReadFile(filepath.Join(dir, "detail-334470.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "detail-334480.html"), detail, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := loadFixtureTemplate(t, "ajou-cms")
+	server := newFixtureServer(dir, template.ArticleIdParam)
+	defer server.Close()
+
+	noticeUrl := server.URL + "/kr/ajou/notice.do"
+	notifier := newFixtureNotifier(t, noticeUrl, template)
+	notifier.BoxCount, notifier.MaxNum = 1, 1189
+	// state set으로 전송 기록을 지운 뒤처럼 고정 공지의 전송 기록이 없어도 번호 행으로 보내지 않는다.
+	for _, key := range []string{"num:334470", "num:334468"} {
+		notifier.seenNotices[key] = ""
+	}
+	notifier.pinned = map[string]Notice{"334455": {ID: "공지", Title: "고정", Url: noticeUrl + "?mode=view&articleNo=334455"}}
+
+	ctx := context.Background()
+	for run := 1; run <= 2; run++ {
+		_, err = notifier.scrapeNotice(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	due, err := Store.LoadDueOutboxEntries(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := make(map[string]string, len(due))
+	for _, entry := range due {
+		kinds[entry.ArticleId] += entry.Kind + " "
+	}
+	if kinds["334455"] != EventUnpinned+" " || kinds["334480"] != "num " {
+		t.Errorf("due entries = %v, want 334455 unpinned once and 334480 as a new notice", kinds)
+	}
+	seen, _ := Store.LoadSeenNotices(notifier.EnglishTopic)
+	if _, ok := seen["num:334455"]; !ok {
+		t.Errorf("seen notices = %v, want the numbered row of 334455 recorded", seen)
+	}
+}
+
+// 상세 페이지를 읽지 못해 보내지 못한 고정 공지는 고정 공지로 기록하지 않고, 보낸 뒤에 기록한다.
+func TestCheckPinnedSkipsUnsentPinnedNotice(t *testing.T) {
+	Store = MemoryStore{}.New()
+	dir := t.TempDir()
+	src := filepath.Join("testdata", "ajou-cms")
+	copyFixture := func(name string) {
+		t.Helper()
+		page, err := os.ReadFile(filepath.Join(src, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, name), page, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	copyFixture("list.html")
+	template := loadFixtureTemplate(t, "ajou-cms")
+	server := newFixtureServer(dir, template.ArticleIdParam)
+	defer server.Close()
+
+	notifier := newFixtureNotifier(t, server.URL+"/kr/ajou/notice.do", template)
+	notifier.BoxCount, notifier.MaxNum = 0, 1203
+	for _, key := range []string{"num:334470", "num:334468"} {
+		notifier.seenNotices[key] = ""
+	}
+
+	ctx := context.Background()
+	notices, err := notifier.scrapeNotice(ctx)
+	if err != nil || len(notices) != 0 {
+		t.Fatalf("first run = (%+v, %v), want nothing queued", notices, err)
+	}
+	pinned, _ := Store.LoadPinnedNotices(notifier.EnglishTopic)
+	if len(notifier.pinned) != 0 || len(pinned) != 0 {
+		t.Fatalf("pinned = %v, saved %v, want 334455 not tracked before it is sent", notifier.pinned, pinned)
+	}
+
+	copyFixture("detail-334455.html")
+	notices, err = notifier.scrapeNotice(ctx)
+	if err != nil || len(notices) != 1 {
+		t.Fatalf("second run = (%+v, %v), want the pinned notice queued", notices, err)
+	}
+	pinned, _ = Store.LoadPinnedNotices(notifier.EnglishTopic)
+	if _, ok := pinned["334455"]; !ok {
+		t.Errorf("saved pinned notices = %v, want 334455 tracked once it is queued", pinned)
+	}
+}
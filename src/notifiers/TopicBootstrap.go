@@ -0,0 +1,73 @@
+package notifiers
+
+import (
+	"context"
+	"fmt"
+
+	. "Notifier/src/store"
+	. "Notifier/src/utils"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 현재 목록을 기준값으로 다시 기록한다. 운영 명령의 state reset에서 쓴다.
+func (notifier *BaseNotifier) Bootstrap(ctx context.Context) error {
+	doc, err := notifier.fetchDocument(ctx, notifier.NoticeUrl, "list")
+	if err != nil {
+		return err
+	}
+	err = notifier.checkHTML(doc)
+	if err != nil {
+		return err
+	}
+	return notifier.bootstrapTopic(ctx, doc)
+}
+
+// 처음 보는 topic은 현재 목록의 공지를 모두 이미 보낸 것으로 기록하고 BoxCount/MaxNum 기준값을 저장한다.
+// 아무것도 전송하지 않으며, 저장에 실패하면 다음 실행에서 다시 시도한다.
+func (notifier *BaseNotifier) bootstrapTopic(ctx context.Context, doc *goquery.Document) error {
+	logger := LoggerFrom(ctx)
+
+	boxNoticeSels := doc.Find(notifier.Template.BoxNoticeSelector)
+	numNoticeSels := doc.Find(notifier.Template.NumNoticeSelector)
+	maxNum, err := parseMaxNum(numNoticeSels)
+	if err != nil {
+		return fmt.Errorf("failed to parse notice number: %w", err)
+	}
+	boxCount := boxNoticeSels.Length()
+
+	for kind, sels := range map[string]*goquery.Selection{"box": boxNoticeSels, "num": numNoticeSels} {
+		for i := range sels.Nodes {
+			rowNotice := notifier.getRowNotice(sels.Eq(i))
+			articleId, fingerprint := notifier.rowIdentity(rowNotice)
+			err = notifier.markSeen(kind, articleId, fingerprint)
+			if err != nil {
+				return fmt.Errorf("failed to save seen notice: %w", err)
+			}
+		}
+	}
+
+	pinned := notifier.rowsByArticleId(boxNoticeSels)
+	err = Store.SavePinnedNotices(notifier.EnglishTopic, pinned)
+	if err != nil {
+		return fmt.Errorf("failed to save pinned notices: %w", err)
+	}
+	notifier.pinned = pinned
+
+	// 기준값이 저장되어야 bootstrap이 끝난 것으로 본다.
+	err = Store.SaveCursor(notifier.EnglishTopic, "box", boxCount)
+	if err == nil {
+		err = Store.SaveCursor(notifier.EnglishTopic, "num", maxNum)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save notice state: %w", err)
+	}
+
+	notifier.state.mu.Lock()
+	notifier.BoxCount = boxCount
+	notifier.MaxNum = maxNum
+	notifier.state.mu.Unlock()
+	notifier.bootstrap = false
+
+	logger.Info("Topic bootstrapped", "box_count", boxCount, "max_num", maxNum, "seen_notices", len(notifier.seenNotices))
+	return nil
+}
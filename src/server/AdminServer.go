@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	. "Notifier/models"
+	. "Notifier/src/notifiers"
+	. "Notifier/src/store"
+	. "Notifier/src/utils"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// 컨테이너에서 노출하는 관리용 HTTP 서버. 공지 피드(/feeds)도 여기서 제공한다.
+// run은 즉시 크롤링 요청을 받았을 때 notifier를 실행하는 함수다.
+// 피드와 같은 포트로 공개되므로 상태를 바꾸는 요청에는 token이 있어야 하고, token이 비어 있으면 받지 않는다.
+type AdminServer struct {
+	notifiers map[string]Notifier
+	topics    []string
+	token     []byte
+	run       func(Notifier)
+	mux       *http.ServeMux
+	server    *http.Server
+}
+
+func (AdminServer) New(notifiers []Notifier, token []byte, run func(Notifier)) *AdminServer {
+	server := &AdminServer{
+		notifiers: make(map[string]Notifier, len(notifiers)),
+		topics:    make([]string, 0, len(notifiers)),
+		token:     token,
+		run:       run,
+		mux:       http.NewServeMux(),
+	}
+	server.server = &http.Server{
+		Handler:           server,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	for _, notifier := range notifiers {
+		server.notifiers[notifier.Topic()] = notifier
+		server.topics = append(server.topics, notifier.Topic())
+	}
+
+	server.mux.HandleFunc("GET /healthz", server.healthz)
+	server.mux.HandleFunc("GET /readyz", server.readyz)
+	server.mux.Handle("GET /metrics", promhttp.Handler())
+	server.mux.HandleFunc("GET /notifiers", server.listNotifiers)
+	server.mux.HandleFunc("GET /notifiers/{topic}", server.getNotifier)
+	server.mux.HandleFunc("POST /notifiers/{topic}/crawl", server.authorized(server.crawl))
+	server.mux.HandleFunc("POST /notifiers/{topic}/pause", server.authorized(server.pause))
+	server.mux.HandleFunc("POST /notifiers/{topic}/resume", server.authorized(server.resume))
+	server.mux.HandleFunc("GET /feeds/{file}", server.topicFeed)
+	server.mux.HandleFunc("GET /feeds.xml", server.aggregateFeed)
+	server.mux.HandleFunc("GET /feeds.json", server.aggregateFeed)
+
+	return server
+}
+
+func (server *AdminServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mux.ServeHTTP(w, r)
+}
+
+// Shutdown을 호출하면 http.ErrServerClosed를 반환한다.
+func (server *AdminServer) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return server.server.Serve(listener)
+}
+
+func (server *AdminServer) Shutdown(ctx context.Context) error {
+	return server.server.Shutdown(ctx)
+}
+
+func (server *AdminServer) healthz(w http.ResponseWriter, _ *http.Request) {
+	writeJson(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// 상태 저장소와 Redis에 모두 연결되어야 준비된 것으로 본다.
+func (server *AdminServer) readyz(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]string{"store": "ok", "redis": "ok"}
+	status := http.StatusOK
+
+	if err := Store.Ping(r.Context()); err != nil {
+		checks["store"] = err.Error()
+		status = http.StatusServiceUnavailable
+	}
+	if err := PingRedis(r.Context()); err != nil {
+		checks["redis"] = err.Error()
+		status = http.StatusServiceUnavailable
+	}
+
+	writeJson(w, status, checks)
+}
+
+func (server *AdminServer) listNotifiers(w http.ResponseWriter, _ *http.Request) {
+	statuses := make([]NotifierStatus, 0, len(server.topics))
+	for _, topic := range server.topics {
+		statuses = append(statuses, server.notifiers[topic].Status())
+	}
+	writeJson(w, http.StatusOK, statuses)
+}
+
+func (server *AdminServer) getNotifier(w http.ResponseWriter, r *http.Request) {
+	notifier, ok := server.lookup(w, r)
+	if !ok {
+		return
+	}
+	writeJson(w, http.StatusOK, notifier.Status())
+}
+
+// 다음 주기를 기다리지 않고 바로 크롤링한다. 일시 정지되었거나 실행 중인 topic은 크롤링하지 않는다.
+func (server *AdminServer) crawl(w http.ResponseWriter, r *http.Request) {
+	notifier, ok := server.lookup(w, r)
+	if !ok {
+		return
+	}
+	status := notifier.Status()
+	if status.Paused {
+		writeJson(w, http.StatusConflict, map[string]string{"error": "notifier is paused"})
+		return
+	}
+	if status.Running {
+		writeJson(w, http.StatusConflict, map[string]string{"error": "notifier is already running"})
+		return
+	}
+	server.run(notifier)
+	writeJson(w, http.StatusAccepted, notifier.Status())
+}
+
+func (server *AdminServer) pause(w http.ResponseWriter, r *http.Request) {
+	notifier, ok := server.lookup(w, r)
+	if !ok {
+		return
+	}
+	notifier.Pause()
+	writeJson(w, http.StatusOK, notifier.Status())
+}
+
+func (server *AdminServer) resume(w http.ResponseWriter, r *http.Request) {
+	notifier, ok := server.lookup(w, r)
+	if !ok {
+		return
+	}
+	notifier.Resume()
+	writeJson(w, http.StatusOK, notifier.Status())
+}
+
+// Authorization: Bearer <token> 헤더가 token과 같을 때만 next를 실행한다.
+func (server *AdminServer) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(server.token) == 0 {
+			writeJson(w, http.StatusForbidden, map[string]string{"error": "admin token is not configured"})
+			return
+		}
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), server.token) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJson(w, http.StatusUnauthorized, map[string]string{"error": "invalid admin token"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (server *AdminServer) lookup(w http.ResponseWriter, r *http.Request) (Notifier, bool) {
+	notifier, ok := server.notifiers[r.PathValue("topic")]
+	if !ok {
+		writeJson(w, http.StatusNotFound, map[string]string{"error": "unknown topic"})
+	}
+	return notifier, ok
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		Logger.Error("Failed to write response", "error", err)
+	}
+}
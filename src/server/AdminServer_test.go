@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "Notifier/models"
+	. "Notifier/src/notifiers"
+)
+
+type stubNotifier struct {
+	paused bool
+}
+
+func (notifier *stubNotifier) Notify(context.Context)                  {}
+func (notifier *stubNotifier) Recheck(context.Context, time.Time, int) {}
+func (notifier *stubNotifier) Topic() string                           { return "Stub" }
+func (notifier *stubNotifier) Status() NotifierStatus {
+	return NotifierStatus{EnglishTopic: "Stub", Paused: notifier.paused}
+}
+func (notifier *stubNotifier) Pause()           { notifier.paused = true }
+func (notifier *stubNotifier) Resume()          { notifier.paused = false }
+func (notifier *stubNotifier) Delivered(Notice) {}
+
+// 상태를 바꾸는 요청은 token이 맞을 때만 받고, 조회는 token 없이도 된다.
+func TestAdminServerRequiresTokenForControlRoutes(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		want          int
+	}{
+		{"token not configured", "", "Bearer ", http.StatusForbidden},
+		{"missing header", "secret", "", http.StatusUnauthorized},
+		{"wrong token", "secret", "Bearer guess", http.StatusUnauthorized},
+		{"valid token", "secret", "Bearer secret", http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			notifier := &stubNotifier{}
+			server := AdminServer{}.New([]Notifier{notifier}, []byte(test.token), func(Notifier) {})
+
+			req := httptest.NewRequest(http.MethodPost, "/notifiers/Stub/pause", nil)
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+			if recorder.Code != test.want {
+				t.Errorf("status = %d, want %d", recorder.Code, test.want)
+			}
+			if notifier.paused != (test.want == http.StatusOK) {
+				t.Errorf("paused = %v after status %d", notifier.paused, recorder.Code)
+			}
+
+			recorder = httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/notifiers/Stub", nil))
+			if recorder.Code != http.StatusOK {
+				t.Errorf("GET status = %d, want 200", recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	. "Notifier/src/feeds"
+	. "Notifier/src/store"
+	. "Notifier/src/utils"
+)
+
+const (
+	defaultFeedLimit = 50
+	maxFeedLimit     = 200
+)
+
+// /feeds/{englishTopic}.xml(RSS, ?format=atom이면 Atom)과 .json(JSON Feed)을 돌려준다.
+func (server *AdminServer) topicFeed(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	topic, extension, ok := strings.Cut(file, ".")
+	if !ok {
+		writeJson(w, http.StatusNotFound, map[string]string{"error": "feed must end with .xml or .json"})
+		return
+	}
+	notifier, ok := server.notifiers[topic]
+	if !ok {
+		writeJson(w, http.StatusNotFound, map[string]string{"error": "unknown topic"})
+		return
+	}
+	status := notifier.Status()
+	feed := Feed{
+		Title:       status.KoreanTopic,
+		Link:        status.NoticeUrl,
+		Description: status.KoreanTopic + " 공지사항",
+	}
+	server.writeFeed(w, r, feed, []string{topic}, extension)
+}
+
+// /feeds.xml?topic=A&topic=B 처럼 여러 topic을 모은 피드를 돌려준다. topic=A,B도 받는다.
+func (server *AdminServer) aggregateFeed(w http.ResponseWriter, r *http.Request) {
+	topics := make([]string, 0)
+	titles := make([]string, 0)
+	for _, value := range r.URL.Query()["topic"] {
+		for _, topic := range strings.Split(value, ",") {
+			topic = strings.TrimSpace(topic)
+			if topic == "" {
+				continue
+			}
+			notifier, ok := server.notifiers[topic]
+			if !ok {
+				writeJson(w, http.StatusNotFound, map[string]string{"error": "unknown topic " + topic})
+				return
+			}
+			topics = append(topics, topic)
+			titles = append(titles, notifier.Status().KoreanTopic)
+		}
+	}
+	if len(topics) == 0 {
+		writeJson(w, http.StatusBadRequest, map[string]string{"error": "at least one topic query parameter is required"})
+		return
+	}
+
+	feed := Feed{
+		Title:       strings.Join(titles, ", "),
+		Link:        feedBaseUrl(r),
+		Description: strings.Join(titles, ", ") + " 공지사항",
+		PrefixTopic: true,
+	}
+	extension := strings.TrimPrefix(r.URL.Path, "/feeds.")
+	server.writeFeed(w, r, feed, topics, extension)
+}
+
+func (server *AdminServer) writeFeed(w http.ResponseWriter, r *http.Request, feed Feed, topics []string, extension string) {
+	limit := defaultFeedLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeJson(w, http.StatusBadRequest, map[string]string{"error": "invalid limit"})
+			return
+		}
+		limit = min(parsed, maxFeedLimit)
+	}
+
+	items, err := Store.LoadArchivedNotices(topics, limit)
+	if err != nil {
+		Logger.Error("Failed to load archived notices", "topics", topics, "error", err)
+		writeJson(w, http.StatusInternalServerError, map[string]string{"error": "failed to load notices"})
+		return
+	}
+	feed.Items = items
+	feed.SelfUrl = feedBaseUrl(r) + r.URL.RequestURI()
+
+	var body []byte
+	var contentType string
+	switch {
+	case extension == "json":
+		body, err = feed.JSON()
+		contentType = JSONContentType
+	case extension == "xml" && r.URL.Query().Get("format") == "atom":
+		body, err = feed.Atom(time.Now())
+		contentType = AtomContentType
+	case extension == "xml":
+		body, err = feed.RSS(time.Now())
+		contentType = RSSContentType
+	default:
+		writeJson(w, http.StatusNotFound, map[string]string{"error": "feed must end with .xml or .json"})
+		return
+	}
+	if err != nil {
+		Logger.Error("Failed to render feed", "topics", topics, "error", err)
+		writeJson(w, http.StatusInternalServerError, map[string]string{"error": "failed to render feed"})
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(body)
+}
+
+// 리버스 프록시 뒤에서는 X-Forwarded-Proto/Host로 외부 주소를 만든다.
+func feedBaseUrl(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	host := r.Host
+	if forwardedHost := r.Header.Get("X-Forwarded-Host"); forwardedHost != "" {
+		host = forwardedHost
+	}
+	return scheme + "://" + host
+}
@@ -0,0 +1,89 @@
+// 웹훅 본문을 HMAC-SHA256으로 서명하고 검증한다.
+// 받는 쪽(백엔드, 테스트 수신기)에서도 이 패키지만 가져다 쓸 수 있도록 다른 패키지에 의존하지 않는다.
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// 서명한 시각(Unix 초)
+	TimestampHeader = "X-Crawler-Timestamp"
+	// 보낼 때마다 새로 만드는 식별자. 같은 본문을 같은 초에 다시 보내도 서명이 달라진다.
+	DeliveryHeader = "X-Crawler-Delivery"
+	// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + delivery + "." + body))
+	SignatureHeader = "X-Crawler-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+var (
+	ErrMissingSignature = errors.New("missing signature headers")
+	ErrInvalidTimestamp = errors.New("invalid signature timestamp")
+	ErrExpiredTimestamp = errors.New("signature timestamp outside tolerance")
+	ErrInvalidSignature = errors.New("signature mismatch")
+	ErrReplayed         = errors.New("delivery already received")
+)
+
+// timestamp, delivery와 본문에 대한 서명 헤더 값을 만든다.
+func Sign(secret []byte, timestamp time.Time, delivery string, body []byte) string {
+	return signaturePrefix + hex.EncodeToString(mac(secret, strconv.FormatInt(timestamp.Unix(), 10), delivery, body))
+}
+
+// 요청에 새 delivery 식별자와 서명 헤더를 붙인다. body는 요청 본문과 같아야 한다.
+// 재시도할 때도 요청마다 다시 불러야 받는 쪽에서 재전송으로 보지 않는다.
+func SignRequest(req *http.Request, secret []byte, timestamp time.Time, body []byte) {
+	delivery := NewDeliveryId()
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	req.Header.Set(DeliveryHeader, delivery)
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, delivery, body))
+}
+
+func NewDeliveryId() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// 서명이 맞는지, timestamp가 now에서 tolerance 안에 있는지 확인한다. 재사용 여부는 Verifier가 확인한다.
+func Verify(secret []byte, timestampHeader, deliveryHeader, signatureHeader string, body []byte, now time.Time, tolerance time.Duration) error {
+	if timestampHeader == "" || deliveryHeader == "" || signatureHeader == "" {
+		return ErrMissingSignature
+	}
+	seconds, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrExpiredTimestamp
+	}
+
+	signature, ok := strings.CutPrefix(signatureHeader, signaturePrefix)
+	if !ok {
+		return ErrInvalidSignature
+	}
+	given, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(given, mac(secret, timestampHeader, deliveryHeader, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func mac(secret []byte, timestamp, delivery string, body []byte) []byte {
+	hash := hmac.New(sha256.New, secret)
+	hash.Write([]byte(timestamp))
+	hash.Write([]byte("."))
+	hash.Write([]byte(delivery))
+	hash.Write([]byte("."))
+	hash.Write(body)
+	return hash.Sum(nil)
+}
@@ -0,0 +1,105 @@
+package signature
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = []byte("test-secret")
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1725000000, 0)
+	body := []byte(`{"id":"1203","title":"교내 근로장학생 모집"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	delivery := "3f1c2a"
+	signature := Sign(secret, now, delivery, body)
+
+	tests := []struct {
+		name      string
+		secret    []byte
+		timestamp string
+		delivery  string
+		signature string
+		body      []byte
+		now       time.Time
+		want      error
+	}{
+		{"valid", secret, timestamp, delivery, signature, body, now, nil},
+		{"clock skew within tolerance", secret, timestamp, delivery, signature, body, now.Add(-4 * time.Minute), nil},
+		{"missing headers", secret, "", "", "", body, now, ErrMissingSignature},
+		{"missing delivery", secret, timestamp, "", signature, body, now, ErrMissingSignature},
+		{"malformed timestamp", secret, "yesterday", delivery, signature, body, now, ErrInvalidTimestamp},
+		{"expired", secret, timestamp, delivery, signature, body, now.Add(6 * time.Minute), ErrExpiredTimestamp},
+		{"tampered body", secret, timestamp, delivery, signature, []byte(`{"id":"1203","title":"가짜 공지"}`), now, ErrInvalidSignature},
+		{"wrong secret", []byte("leaked-token-only"), timestamp, delivery, signature, body, now, ErrInvalidSignature},
+		{"timestamp swapped", secret, strconv.FormatInt(now.Unix()+1, 10), delivery, signature, body, now, ErrInvalidSignature},
+		{"delivery swapped", secret, timestamp, "9d0e4b", signature, body, now, ErrInvalidSignature},
+		{"missing prefix", secret, timestamp, delivery, strings.TrimPrefix(signature, "sha256="), body, now, ErrInvalidSignature},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Verify(test.secret, test.timestamp, test.delivery, test.signature, test.body, test.now, 5*time.Minute)
+			if !errors.Is(err, test.want) {
+				t.Errorf("Verify = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestMiddlewareRejectsReplayedRequests(t *testing.T) {
+	verifier := Verifier{}.New(secret, 5*time.Minute)
+	var received []string
+	server := httptest.NewServer(verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, string(body))
+	})))
+	defer server.Close()
+
+	body := []byte(`{"id":"512"}`)
+	newRequest := func() *http.Request {
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(string(body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+
+	// 토큰이 거부되어 같은 본문을 같은 초에 다시 보내는 경우
+	now := time.Now()
+	signed := newRequest()
+	SignRequest(signed, secret, now, body)
+	retried := newRequest()
+	SignRequest(retried, secret, now, body)
+	replayed := newRequest()
+	replayed.Header = signed.Header.Clone()
+
+	for _, test := range []struct {
+		name string
+		req  *http.Request
+		want int
+	}{
+		{"signed", signed, http.StatusOK},
+		{"retried", retried, http.StatusOK},
+		{"replayed", replayed, http.StatusUnauthorized},
+		{"unsigned", newRequest(), http.StatusUnauthorized},
+	} {
+		resp, err := http.DefaultClient.Do(test.req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.want {
+			t.Errorf("%s request: status = %d, want %d", test.name, resp.StatusCode, test.want)
+		}
+	}
+
+	if len(received) != 2 || received[0] != string(body) || received[1] != string(body) {
+		t.Errorf("handler received %q, want the signed and retried bodies", received)
+	}
+}
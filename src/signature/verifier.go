@@ -0,0 +1,92 @@
+package signature
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 서명을 검증하고, tolerance 안에서 같은 delivery 식별자가 다시 오면 재전송 공격으로 보고 거부한다.
+type Verifier struct {
+	secret    []byte
+	tolerance time.Duration
+	maxBody   int64
+	now       func() time.Time
+	seen      *seenDeliveries
+}
+
+type seenDeliveries struct {
+	mu         sync.Mutex
+	expiresAt  map[string]time.Time
+	lastPruned time.Time
+}
+
+func (Verifier) New(secret []byte, tolerance time.Duration) *Verifier {
+	return &Verifier{
+		secret:    secret,
+		tolerance: tolerance,
+		maxBody:   10 << 20,
+		now:       time.Now,
+		seen:      &seenDeliveries{expiresAt: make(map[string]time.Time)},
+	}
+}
+
+func (verifier *Verifier) Verify(timestampHeader, deliveryHeader, signatureHeader string, body []byte) error {
+	now := verifier.now()
+	err := Verify(verifier.secret, timestampHeader, deliveryHeader, signatureHeader, body, now, verifier.tolerance)
+	if err != nil {
+		return err
+	}
+	return verifier.seen.remember(deliveryHeader, now, verifier.tolerance)
+}
+
+// 요청 본문을 읽어 검증하고, 다음 핸들러가 다시 읽을 수 있도록 본문을 되돌려 놓는다.
+func (verifier *Verifier) VerifyRequest(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, verifier.maxBody))
+	if err != nil {
+		return nil, err
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	err = verifier.Verify(r.Header.Get(TimestampHeader), r.Header.Get(DeliveryHeader), r.Header.Get(SignatureHeader), body)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// 서명이 맞지 않는 요청에는 401을 응답한다.
+func (verifier *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := verifier.VerifyRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// 서명에 delivery가 들어가므로 위조할 수 없다. timestamp 허용 범위를 지나면 어차피 거부되므로 그때까지만 기억한다.
+func (seen *seenDeliveries) remember(delivery string, now time.Time, tolerance time.Duration) error {
+	seen.mu.Lock()
+	defer seen.mu.Unlock()
+
+	if now.Sub(seen.lastPruned) > tolerance {
+		for key, expiresAt := range seen.expiresAt {
+			if now.After(expiresAt) {
+				delete(seen.expiresAt, key)
+			}
+		}
+		seen.lastPruned = now
+	}
+
+	if expiresAt, ok := seen.expiresAt[delivery]; ok && !now.After(expiresAt) {
+		return ErrReplayed
+	}
+	seen.expiresAt[delivery] = now.Add(2 * tolerance)
+	return nil
+}
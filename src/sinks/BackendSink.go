@@ -0,0 +1,35 @@
+package sinks
+
+import (
+	"context"
+
+	. "Notifier/models"
+	. "Notifier/src/utils"
+)
+
+// 기존 백엔드 웹훅. crawling-token과 서명을 붙여 SendCrawlingWebhook으로 보낸다.
+// 이벤트는 공지로 저장되지 않도록 eventUrl이 있을 때만 그 주소로 보낸다.
+type BackendSink struct {
+	name     string
+	url      string
+	eventUrl string
+}
+
+func (BackendSink) New(name, url, eventUrl string) *BackendSink {
+	return &BackendSink{name: name, url: url, eventUrl: eventUrl}
+}
+
+func (sink *BackendSink) Name() string {
+	return sink.name
+}
+
+func (sink *BackendSink) Send(ctx context.Context, notice Notice) error {
+	return SendCrawlingWebhook(ctx, sink.url, notice)
+}
+
+func (sink *BackendSink) SendEvent(ctx context.Context, event NoticeEvent) error {
+	if sink.eventUrl == "" {
+		return nil
+	}
+	return SendCrawlingEvent(ctx, sink.eventUrl, event.Event, event)
+}
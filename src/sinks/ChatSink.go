@@ -0,0 +1,76 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	. "Notifier/models"
+	. "Notifier/src/utils"
+)
+
+// Slack, Discord, Mattermost의 incoming webhook. 제목과 링크만 보낸다.
+type ChatSink struct {
+	name     string
+	platform string // slack, discord, mattermost
+	url      string
+}
+
+func (ChatSink) New(name, platform, url string) *ChatSink {
+	return &ChatSink{name: name, platform: platform, url: url}
+}
+
+func (sink *ChatSink) Name() string {
+	return sink.name
+}
+
+func (sink *ChatSink) Send(ctx context.Context, notice Notice) error {
+	return sink.post(ctx, sink.payload(noticeTitle(notice), notice.Url))
+}
+
+func (sink *ChatSink) SendEvent(ctx context.Context, event NoticeEvent) error {
+	return sink.post(ctx, sink.payload(eventTitle(event), event.Notice.Url))
+}
+
+func (sink *ChatSink) post(ctx context.Context, message map[string]string) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s webhook status code error: %d", sink.platform, resp.StatusCode)
+	}
+	return nil
+}
+
+// 플랫폼마다 메시지 필드와 링크 문법이 다르다.
+func (sink *ChatSink) payload(title, url string) map[string]string {
+	switch sink.platform {
+	case "slack":
+		escaper := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+		return map[string]string{"text": fmt.Sprintf("<%s|%s>", url, escaper.Replace(title))}
+	case "discord":
+		return map[string]string{"content": fmt.Sprintf("[%s](<%s>)", markdownEscaper.Replace(title), url)}
+	default:
+		return map[string]string{"text": fmt.Sprintf("[%s](%s)", markdownEscaper.Replace(title), url)}
+	}
+}
+
+// 제목의 대괄호가 Markdown 링크를 깨뜨리지 않게 한다.
+var markdownEscaper = strings.NewReplacer("[", "\\[", "]", "\\]")
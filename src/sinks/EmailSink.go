@@ -0,0 +1,139 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	. "Notifier/models"
+)
+
+// SMTP로 공지 한 건을 메일 한 통으로 보낸다. 서버가 STARTTLS를 지원하면 암호화한다.
+type EmailSink struct {
+	name   string
+	config SMTPConfig
+}
+
+func (EmailSink) New(name string, config SMTPConfig) *EmailSink {
+	return &EmailSink{name: name, config: config}
+}
+
+func (sink *EmailSink) Name() string {
+	return sink.name
+}
+
+func (sink *EmailSink) Send(ctx context.Context, notice Notice) error {
+	return sink.send(ctx, sink.message(noticeTitle(notice), "", notice, time.Now()))
+}
+
+func (sink *EmailSink) SendEvent(ctx context.Context, event NoticeEvent) error {
+	return sink.send(ctx, sink.message(eventTitle(event), changedFields(event), event.Notice, time.Now()))
+}
+
+func (sink *EmailSink) send(ctx context.Context, message []byte) error {
+	addr := net.JoinHostPort(sink.config.Host, strconv.Itoa(sink.config.Port))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	// net/smtp는 ctx를 받지 않으므로 연결에 마감 시각을 건다.
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, sink.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: sink.config.Host})
+		if err != nil {
+			return err
+		}
+	}
+	if sink.config.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", sink.config.Username, sink.config.Password, sink.config.Host))
+		if err != nil {
+			return err
+		}
+	}
+	err = client.Mail(sink.config.From)
+	if err != nil {
+		return err
+	}
+	for _, to := range sink.config.To {
+		err = client.Rcpt(to)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(message)
+	if err != nil {
+		writer.Close()
+		return err
+	}
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// 한글 제목은 RFC 2047로, 본문은 base64로 인코딩한다. changes가 있으면 본문 첫 줄에 적는다.
+func (sink *EmailSink) message(subject, changes string, notice Notice, now time.Time) []byte {
+	var body strings.Builder
+	if changes != "" {
+		body.WriteString("수정된 항목: " + changes + "\r\n\r\n")
+	}
+	body.WriteString(notice.Title + "\r\n")
+	body.WriteString(notice.Url + "\r\n")
+	if notice.Department != "" {
+		body.WriteString(notice.Department + "\r\n")
+	}
+	if notice.Content != "" {
+		// Content의 줄바꿈은 "\n" 문자열로 저장되어 있다.
+		body.WriteString("\r\n" + strings.ReplaceAll(notice.Content, "\\n", "\r\n") + "\r\n")
+	}
+	for _, image := range notice.Images {
+		body.WriteString(image + "\r\n")
+	}
+	if len(notice.Attachments) > 0 {
+		body.WriteString("\r\n첨부파일\r\n")
+		for _, attachment := range notice.Attachments {
+			body.WriteString(attachment.Name + " " + attachment.Url + "\r\n")
+		}
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", sink.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(sink.config.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(body.String()))
+	for len(encoded) > 76 {
+		msg.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	msg.WriteString(encoded + "\r\n")
+	return msg.Bytes()
+}
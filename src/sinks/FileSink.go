@@ -0,0 +1,68 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "Notifier/models"
+)
+
+// 공지 하나를 JSON 한 줄로 파일 끝에 붙인다. 매번 파일을 열어 logrotate로 옮겨도 된다.
+type FileSink struct {
+	name string
+	path string
+	mu   *sync.Mutex
+}
+
+func (FileSink) New(name, path string) *FileSink {
+	return &FileSink{name: name, path: path, mu: &sync.Mutex{}}
+}
+
+func (sink *FileSink) Name() string {
+	return sink.name
+}
+
+func (sink *FileSink) Send(_ context.Context, notice Notice) error {
+	return sink.write(notice)
+}
+
+func (sink *FileSink) SendEvent(_ context.Context, event NoticeEvent) error {
+	return sink.write(event)
+}
+
+func (sink *FileSink) write(value any) error {
+	line, err := marshalLine(value)
+	if err != nil {
+		return err
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	err = os.MkdirAll(filepath.Dir(sink.path), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(sink.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(line)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// URL의 &를 이스케이프하지 않은 JSON 한 줄
+func marshalLine(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(value)
+	return buf.Bytes(), err
+}
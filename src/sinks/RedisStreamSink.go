@@ -0,0 +1,78 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	. "Notifier/models"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	defaultStream       = "notices"
+	defaultStreamMaxLen = 10000
+)
+
+// 공지를 Redis Stream에 XADD한다. 메시지 id는 Redis가 만드는 단조 증가 id(*)라서
+// 소비자 그룹이 XREADGROUP/XACK로 그대로 읽을 수 있다.
+// stream에 {topic}이 있으면 topic별 스트림에, 없으면 하나의 스트림에 topic 필드와 함께 넣는다.
+// 이벤트는 같은 스트림에 event, changes 필드를 더해 넣는다.
+type RedisStreamSink struct {
+	name   string
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+func (RedisStreamSink) New(name string, client *redis.Client, stream string, maxLen int64) *RedisStreamSink {
+	if stream == "" {
+		stream = defaultStream
+	}
+	if maxLen <= 0 {
+		maxLen = defaultStreamMaxLen
+	}
+	return &RedisStreamSink{name: name, client: client, stream: stream, maxLen: maxLen}
+}
+
+func (sink *RedisStreamSink) Name() string {
+	return sink.name
+}
+
+func (sink *RedisStreamSink) Send(ctx context.Context, notice Notice) error {
+	return sink.add(ctx, notice)
+}
+
+func (sink *RedisStreamSink) SendEvent(ctx context.Context, event NoticeEvent) error {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return err
+	}
+	return sink.add(ctx, event.Notice, "event", event.Event, "changes", string(changes))
+}
+
+func (sink *RedisStreamSink) add(ctx context.Context, notice Notice, extra ...any) error {
+	payload, err := json.Marshal(notice)
+	if err != nil {
+		return err
+	}
+	values := []any{
+		"topic", notice.EnglishTopic,
+		"id", notice.ID,
+		"url", notice.Url,
+		"title", notice.Title,
+		"notice", string(payload),
+	}
+	// MAXLEN ~로 자르면 Redis가 노드 단위로 지워 XADD가 느려지지 않는다.
+	return sink.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: sink.streamFor(notice),
+		MaxLen: sink.maxLen,
+		Approx: true,
+		ID:     "*",
+		Values: append(values, extra...),
+	}).Err()
+}
+
+func (sink *RedisStreamSink) streamFor(notice Notice) string {
+	return strings.ReplaceAll(sink.stream, "{topic}", notice.EnglishTopic)
+}
@@ -0,0 +1,108 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "Notifier/models"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func TestRedisStreamSinkPerTopic(t *testing.T) {
+	_, client := newTestRedis(t)
+	ctx := context.Background()
+	sink := RedisStreamSink{}.New("stream", client, "notices:{topic}", 0)
+
+	for _, topic := range []string{"AjouNormal", "AjouNormal", "Software"} {
+		notice := testNotice
+		notice.EnglishTopic = topic
+		err := sink.Send(ctx, notice)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	messages, err := client.XRange(ctx, "notices:AjouNormal", "-", "+").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 2 || messages[0].ID >= messages[1].ID {
+		t.Fatalf("messages = %+v, want two in increasing id order", messages)
+	}
+	if messages[0].Values["topic"] != "AjouNormal" || messages[0].Values["id"] != "1203" {
+		t.Errorf("message fields = %v", messages[0].Values)
+	}
+	var notice Notice
+	err = json.Unmarshal([]byte(messages[0].Values["notice"].(string)), &notice)
+	if err != nil || notice.Title != testNotice.Title {
+		t.Errorf("notice field = %v (%v)", messages[0].Values["notice"], err)
+	}
+
+	length, err := client.XLen(ctx, "notices:Software").Result()
+	if err != nil || length != 1 {
+		t.Errorf("notices:Software length = %d (%v), want 1", length, err)
+	}
+}
+
+func TestRedisStreamSinkConsumerGroup(t *testing.T) {
+	_, client := newTestRedis(t)
+	ctx := context.Background()
+	sink := RedisStreamSink{}.New("stream", client, "notices", 0)
+
+	err := client.XGroupCreateMkStream(ctx, "notices", "backend", "$").Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sink.Send(ctx, testNotice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	streams, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    "backend",
+		Consumer: "worker-1",
+		Streams:  []string{"notices", ">"},
+		Count:    10,
+		Block:    -1,
+	}).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(streams) != 1 || len(streams[0].Messages) != 1 {
+		t.Fatalf("XREADGROUP = %+v, want one message", streams)
+	}
+	err = client.XAck(ctx, "notices", "backend", streams[0].Messages[0].ID).Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisStreamSinkTrimsStream(t *testing.T) {
+	server, client := newTestRedis(t)
+	ctx := context.Background()
+	sink := RedisStreamSink{}.New("stream", client, "notices", 3)
+
+	for i := 0; i < 10; i++ {
+		err := sink.Send(ctx, testNotice)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// MAXLEN ~는 실제 Redis에서 3보다 조금 더 남길 수 있다. miniredis는 정확히 자른다.
+	length, err := client.XLen(ctx, "notices").Result()
+	if err != nil || length > 3 {
+		t.Errorf("stream length = %d (%v), want at most 3", length, err)
+	}
+	if !server.Exists("notices") {
+		t.Error("stream was not created")
+	}
+}
@@ -0,0 +1,167 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	. "Notifier/models"
+	. "Notifier/src/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+// notifierConfigs.json에 sinks가 없는 topic이 쓰는 sink
+const DefaultSinkName = "backend"
+
+// 공지를 받는 곳. Send가 에러를 반환하면 OutboxWorker가 나중에 다시 보낸다.
+// SendEvent는 이미 보낸 공지가 바뀌었을 때 호출한다.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, notice Notice) error
+	SendEvent(ctx context.Context, event NoticeEvent) error
+}
+
+// config/sinks.json을 읽고 문자열 값의 ${ENV}를 환경변수로 바꾼다.
+// 파일이 없으면 WEBHOOK_ENDPOINT로 보내는 backend sink 하나만 쓰고, 이벤트는 WEBHOOK_EVENT_ENDPOINT가 있을 때만 보낸다.
+func LoadSinkConfigs(path string) []SinkConfig {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []SinkConfig{{Name: DefaultSinkName, Type: "backend", Url: os.Getenv("WEBHOOK_ENDPOINT"), EventUrl: os.Getenv("WEBHOOK_EVENT_ENDPOINT")}}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	var configs []SinkConfig
+	err = json.NewDecoder(file).Decode(&configs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i := range configs {
+		configs[i] = expandEnv(configs[i])
+	}
+	return configs
+}
+
+func expandEnv(config SinkConfig) SinkConfig {
+	config.Url = os.ExpandEnv(config.Url)
+	config.EventUrl = os.ExpandEnv(config.EventUrl)
+	config.Path = os.ExpandEnv(config.Path)
+	config.Stream = os.ExpandEnv(config.Stream)
+	config.SMTP.Host = os.ExpandEnv(config.SMTP.Host)
+	config.SMTP.Username = os.ExpandEnv(config.SMTP.Username)
+	config.SMTP.Password = os.ExpandEnv(config.SMTP.Password)
+	config.SMTP.From = os.ExpandEnv(config.SMTP.From)
+	to := make([]string, 0, len(config.SMTP.To))
+	for _, address := range config.SMTP.To {
+		to = append(to, os.ExpandEnv(address))
+	}
+	config.SMTP.To = to
+	return config
+}
+
+// 설정에 필요한 값이 빠져 있으면 에러를 반환한다.
+func NewSink(config SinkConfig) (Sink, error) {
+	if config.Name == "" {
+		return nil, errors.New("sink name is empty")
+	}
+	switch config.Type {
+	case "backend":
+		if config.Url == "" {
+			return nil, fmt.Errorf("sink %s: url is empty", config.Name)
+		}
+		return BackendSink{}.New(config.Name, config.Url, config.EventUrl), nil
+	case "slack", "discord", "mattermost":
+		if config.Url == "" {
+			return nil, fmt.Errorf("sink %s: url is empty", config.Name)
+		}
+		return ChatSink{}.New(config.Name, config.Type, config.Url), nil
+	case "email":
+		smtp := config.SMTP
+		if smtp.Host == "" || smtp.Port <= 0 || smtp.From == "" || len(smtp.To) == 0 {
+			return nil, fmt.Errorf("sink %s: smtp host, port, from and to are required", config.Name)
+		}
+		return EmailSink{}.New(config.Name, smtp), nil
+	case "file":
+		if config.Path == "" {
+			return nil, fmt.Errorf("sink %s: path is empty", config.Name)
+		}
+		return FileSink{}.New(config.Name, config.Path), nil
+	case "stdout":
+		return StdoutSink{}.New(config.Name, os.Stdout), nil
+	case "redis-stream":
+		client := RedisClient
+		if config.Url != "" {
+			options, err := redis.ParseURL(config.Url)
+			if err != nil {
+				return nil, fmt.Errorf("sink %s: %w", config.Name, err)
+			}
+			client = redis.NewClient(options)
+		}
+		return RedisStreamSink{}.New(config.Name, client, config.Stream, config.MaxLen), nil
+	default:
+		return nil, fmt.Errorf("sink %s: unknown type %q", config.Name, config.Type)
+	}
+}
+
+// name으로 찾을 수 있게 sink를 만든다. 이름이 겹치면 에러를 반환한다.
+func NewSinks(configs []SinkConfig) (map[string]Sink, error) {
+	sinks := make(map[string]Sink, len(configs))
+	for _, config := range configs {
+		if _, ok := sinks[config.Name]; ok {
+			return nil, fmt.Errorf("duplicate sink name %s", config.Name)
+		}
+		sink, err := NewSink(config)
+		if err != nil {
+			return nil, err
+		}
+		sinks[config.Name] = sink
+	}
+	return sinks, nil
+}
+
+// 사람이 읽는 메시지의 제목
+func noticeTitle(notice Notice) string {
+	if notice.Category != "" {
+		return fmt.Sprintf("[%s] [%s] %s", notice.KoreanTopic, notice.Category, notice.Title)
+	}
+	return fmt.Sprintf("[%s] %s", notice.KoreanTopic, notice.Title)
+}
+
+var eventLabels = map[string]string{
+	"updated":  "수정",
+	"unpinned": "고정 해제",
+	"deleted":  "삭제",
+}
+
+var fieldLabels = map[string]string{
+	"title":   "제목",
+	"content": "본문",
+	"images":  "이미지",
+}
+
+// 이벤트 메시지의 제목. 앞에 [수정] 같은 이벤트 이름을 붙인다.
+func eventTitle(event NoticeEvent) string {
+	label, ok := eventLabels[event.Event]
+	if !ok {
+		label = event.Event
+	}
+	return fmt.Sprintf("[%s] %s", label, noticeTitle(event.Notice))
+}
+
+// 바뀐 필드 이름을 "제목, 본문"처럼 나열한다.
+func changedFields(event NoticeEvent) string {
+	fields := make([]string, 0, len(event.Changes))
+	for _, change := range event.Changes {
+		label, ok := fieldLabels[change.Field]
+		if !ok {
+			label = change.Field
+		}
+		fields = append(fields, label)
+	}
+	return strings.Join(fields, ", ")
+}
@@ -0,0 +1,232 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "Notifier/models"
+	. "Notifier/src/utils"
+)
+
+var testNotice = Notice{
+	ID:          "1203",
+	Category:    "학사",
+	Title:       "2024-2학기 [수강신청] 안내",
+	Url:         "https://ajou.ac.kr/kr/ajou/notice.do?mode=view&articleNo=1203",
+	Content:     "첫 줄\\n둘째 줄",
+	KoreanTopic: "아주대학교-일반",
+}
+
+// eventUrl이 없으면 이벤트를 보내지 않고, 있으면 공지 웹훅이 아닌 eventUrl로 보낸다.
+func TestBackendSinkSendsEventsOnlyToEventUrl(t *testing.T) {
+	_, client := newTestRedis(t)
+	previous := CrawlingToken
+	CrawlingToken = TokenSource{}.New(client, time.Hour)
+	t.Cleanup(func() { CrawlingToken = previous })
+	client.Set(context.Background(), "crawling-token", "token", 0)
+
+	var notices, events []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/notices":
+			notices = append(notices, r.Header.Get("X-Crawler-Event"))
+		case "/events":
+			events = append(events, r.Header.Get("X-Crawler-Event"))
+		}
+	}))
+	defer server.Close()
+	event := NoticeEvent{Event: "updated", Notice: testNotice}
+
+	sink, err := NewSink(SinkConfig{Name: "backend", Type: "backend", Url: server.URL + "/notices"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sink.SendEvent(context.Background(), event)
+	if err != nil || len(notices) != 0 {
+		t.Fatalf("SendEvent without eventUrl = %v with %d requests, want nil and none", err, len(notices))
+	}
+
+	sink, err = NewSink(SinkConfig{Name: "backend", Type: "backend", Url: server.URL + "/notices", EventUrl: server.URL + "/events"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sink.SendEvent(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notices) != 0 || len(events) != 1 || events[0] != "updated" {
+		t.Errorf("notices = %v, events = %v, want one updated event", notices, events)
+	}
+}
+
+func TestChatSinkPayloads(t *testing.T) {
+	tests := []struct {
+		platform string
+		want     map[string]string
+	}{
+		{"slack", map[string]string{"text": "<https://ajou.ac.kr/kr/ajou/notice.do?mode=view&articleNo=1203|[아주대학교-일반] [학사] 2024-2학기 [수강신청] 안내>"}},
+		{"discord", map[string]string{"content": `[\[아주대학교-일반\] \[학사\] 2024-2학기 \[수강신청\] 안내](<https://ajou.ac.kr/kr/ajou/notice.do?mode=view&articleNo=1203>)`}},
+		{"mattermost", map[string]string{"text": `[\[아주대학교-일반\] \[학사\] 2024-2학기 \[수강신청\] 안내](https://ajou.ac.kr/kr/ajou/notice.do?mode=view&articleNo=1203)`}},
+	}
+	for _, test := range tests {
+		t.Run(test.platform, func(t *testing.T) {
+			var got map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &got)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			sink, err := NewSink(SinkConfig{Name: test.platform, Type: test.platform, Url: server.URL})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = sink.Send(context.Background(), testNotice)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range test.want {
+				if got[key] != want {
+					t.Errorf("%s = %q, want %q", key, got[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestChatSinkReturnsErrorOnRejectedMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	err := ChatSink{}.New("slack", "slack", server.URL).Send(context.Background(), testNotice)
+	if err == nil {
+		t.Fatal("expected an error for a 429 response")
+	}
+}
+
+func TestChatSinkEventTitle(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+	}))
+	defer server.Close()
+
+	event := NoticeEvent{Event: "updated", Notice: testNotice, Changes: []FieldChange{{Field: "content", Old: "a", New: "b"}}}
+	err := ChatSink{}.New("slack", "slack", server.URL).SendEvent(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<https://ajou.ac.kr/kr/ajou/notice.do?mode=view&articleNo=1203|[수정] [아주대학교-일반] [학사] 2024-2학기 [수강신청] 안내>"
+	if got["text"] != want {
+		t.Errorf("text = %q, want %q", got["text"], want)
+	}
+}
+
+func TestFileSinkWritesEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	event := NoticeEvent{Event: "updated", Notice: testNotice, Changes: []FieldChange{{Field: "title", Old: "이전 제목", New: testNotice.Title}}}
+	err := FileSink{}.New("archive", path).SendEvent(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got NoticeEvent
+	err = json.Unmarshal(data, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Event != "updated" || got.Notice.ID != testNotice.ID || len(got.Changes) != 1 || got.Changes[0].Old != "이전 제목" {
+		t.Errorf("event = %+v", got)
+	}
+}
+
+func TestFileSinkAppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feeds", "notices.jsonl")
+	sink := FileSink{}.New("archive", path)
+	for _, id := range []string{"1", "2"} {
+		notice := testNotice
+		notice.ID = id
+		err := sink.Send(context.Background(), notice)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("file has %d lines, want 2:\n%s", len(lines), data)
+	}
+	if !strings.Contains(lines[0], "mode=view&articleNo=1203") {
+		t.Errorf("url was escaped: %s", lines[0])
+	}
+	var notice Notice
+	err = json.Unmarshal([]byte(lines[1]), &notice)
+	if err != nil || notice.ID != "2" {
+		t.Errorf("second line = %s (%v), want notice 2", lines[1], err)
+	}
+}
+
+func TestEmailSinkMessage(t *testing.T) {
+	sink := EmailSink{}.New("council", SMTPConfig{From: "crawler@ajou.ac.kr", To: []string{"a@ajou.ac.kr", "b@ajou.ac.kr"}})
+	msg := string(sink.message(noticeTitle(testNotice), "", testNotice, time.Date(2024, 9, 2, 9, 0, 0, 0, time.UTC)))
+
+	header, body, ok := strings.Cut(msg, "\r\n\r\n")
+	if !ok {
+		t.Fatalf("message has no header separator:\n%s", msg)
+	}
+	for _, want := range []string{
+		"To: a@ajou.ac.kr, b@ajou.ac.kr",
+		"Subject: =?UTF-8?b?",
+		"Date: Mon, 02 Sep 2024 09:00:00 +0000",
+		"Content-Transfer-Encoding: base64",
+	} {
+		if !strings.Contains(header, want) {
+			t.Errorf("header missing %q:\n%s", want, header)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSpace(body), "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("body line longer than 76 characters: %q", line)
+		}
+	}
+}
+
+func TestLoadSinkConfigsExpandsEnv(t *testing.T) {
+	t.Setenv("SLACK_WEBHOOK_URL", "https://hooks.slack.com/services/T/B/X")
+	path := filepath.Join(t.TempDir(), "sinks.json")
+	err := os.WriteFile(path, []byte(`[{"name": "council", "type": "slack", "url": "${SLACK_WEBHOOK_URL}"}]`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configs := LoadSinkConfigs(path)
+	if len(configs) != 1 || configs[0].Url != "https://hooks.slack.com/services/T/B/X" {
+		t.Errorf("configs = %+v", configs)
+	}
+
+	t.Setenv("WEBHOOK_ENDPOINT", "http://backend/webhook")
+	configs = LoadSinkConfigs(filepath.Join(t.TempDir(), "missing.json"))
+	if len(configs) != 1 || configs[0].Name != DefaultSinkName || configs[0].Url != "http://backend/webhook" {
+		t.Errorf("default configs = %+v", configs)
+	}
+}
@@ -0,0 +1,43 @@
+package sinks
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	. "Notifier/models"
+)
+
+// 공지를 JSON 한 줄로 출력한다. 로컬 실행이나 다른 프로그램에 파이프로 넘길 때 쓴다.
+type StdoutSink struct {
+	name   string
+	writer io.Writer
+	mu     *sync.Mutex
+}
+
+func (StdoutSink) New(name string, writer io.Writer) *StdoutSink {
+	return &StdoutSink{name: name, writer: writer, mu: &sync.Mutex{}}
+}
+
+func (sink *StdoutSink) Name() string {
+	return sink.name
+}
+
+func (sink *StdoutSink) Send(_ context.Context, notice Notice) error {
+	return sink.write(notice)
+}
+
+func (sink *StdoutSink) SendEvent(_ context.Context, event NoticeEvent) error {
+	return sink.write(event)
+}
+
+func (sink *StdoutSink) write(value any) error {
+	line, err := marshalLine(value)
+	if err != nil {
+		return err
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	_, err = sink.writer.Write(line)
+	return err
+}
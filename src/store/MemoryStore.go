@@ -0,0 +1,272 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	. "Notifier/models"
+)
+
+// 프로세스 안에만 상태를 두는 저장소. 테스트나 일회성 실행에 쓴다.
+type MemoryStore struct {
+	state *memoryState
+}
+
+type memoryState struct {
+	mu      sync.Mutex
+	cursors map[string]int               // cursorKey(topic, kind)
+	seen    map[string]map[string]string // topic별 전송 기록과 지문
+	outbox  []*memoryOutboxEntry
+	nextId  int64
+	archive []ArchivedNotice // 처음 전송한 순서
+	pinned  map[string]map[string]Notice
+}
+
+type memoryOutboxEntry struct {
+	entry         OutboxEntry
+	status        string
+	nextAttemptAt time.Time
+	lastError     string
+}
+
+func (MemoryStore) New() *MemoryStore {
+	return &MemoryStore{state: &memoryState{
+		cursors: make(map[string]int),
+		seen:    make(map[string]map[string]string),
+		pinned:  make(map[string]map[string]Notice),
+	}}
+}
+
+func (store *MemoryStore) Init() error {
+	return nil
+}
+
+func (store *MemoryStore) Ping(_ context.Context) error {
+	return nil
+}
+
+func (store *MemoryStore) Close() error {
+	return nil
+}
+
+func (store *MemoryStore) LoadCursors(topic string) (int, int, error) {
+	store.state.mu.Lock()
+	defer store.state.mu.Unlock()
+
+	boxCount, okBox := store.state.cursors[cursorKey(topic, "box")]
+	maxNum, okNum := store.state.cursors[cursorKey(topic, "num")]
+	if !okBox || !okNum {
+		return 0, 0, fmt.Errorf("%w: %s", ErrTopicNotFound, topic)
+	}
+	return boxCount, maxNum, nil
+}
+
+func (store *MemoryStore) SaveCursor(topic, kind string, value int) error {
+	store.state.mu.Lock()
+	defer store.state.mu.Unlock()
+	store.state.cursors[cursorKey(topic, kind)] = value
+	return nil
+}
+
+func (store *MemoryStore) LoadSeenNotices(topic string) (map[string]string, error) {
+	store.state.mu.Lock()
+	defer store.state.mu.Unlock()
+
+	seen := maps.Clone(store.state.seen[topic])
+	if seen == nil {
+		seen = make(map[string]string)
+	}
+	for _, queued := range store.state.outbox {
+		if queued.entry.Topic == topic && !IsEventKind(queued.entry.Kind) {
+			seen[SeenNoticeKey(queued.entry.Kind, queued.entry.ArticleId)] = queued.entry.Fingerprint
+		}
+	}
+	return seen, nil
+}
+
+func (store *MemoryStore) SaveSeenNotice(topic, kind, articleId, fingerprint string) error {
+	store.state.mu.Lock()
+	defer store.state.mu.Unlock()
+	store.saveSeen(topic, kind, articleId, fingerprint)
+	return nil
+}
+
+func (store *MemoryStore) saveSeen(topic, kind, articleId, fingerprint string) {
+	if store.state.seen[topic] == nil {
+		store.state.seen[topic] = make(map[string]string)
+	}
+	store.state.seen[topic][SeenNoticeKey(kind, articleId)] = fingerprint
+}
+
+func (store *MemoryStore) ClearSeenNotices(topic string) error {
+	store.state.mu.Lock()
+	defer store.state.mu.Unlock()
+
+	delete(store.state.seen, topic)
+	store.state.outbox = slices.DeleteFunc(store.state.outbox, func(queued *memoryOutboxEntry) bool {
+		return queued.entry.Topic == topic && !IsEventKind(queued.entry.Kind) && queued.status != OutboxPending
+	})
+	return nil
+}
+
+func (store *MemoryStore) EnqueueNotices(entries []OutboxEntry) error {
+	store.state.mu.Lock()
+	defer store.state.mu.Unlock()
+
+	for _, entry := range entries {
+		if store.queued(entry) {
+			continue
+		}
+		store.state.nextId++
+		entry.ID = store.state.nextId
+		entry.Attempts = 0
+		store.state.outbox = append(store.state.outbox, &memoryOutboxEntry{
+			entry:         entry,
+			status:        OutboxPending,
+			nextAttemptAt: time.Now(),
+		})
+	}
+	return nil
+}
+
+func (store *MemoryStore) queued(entry OutboxEntry) bool {
+	for _, queued := range store.state.outbox {
+		if queued.entry.Topic == entry.Topic && queued.entry.Kind == entry.Kind && queued.entry.ArticleId == entry.ArticleId &&
+			queued.entry.Sink == entry.Sink && queued.entry.Fingerprint == entry.Fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+func (store *MemoryStore) LoadDueOutboxEntries(limit int) ([]OutboxEntry, error) {
+	store.state.mu.Lock()
+	defer store.state.mu.Unlock()
+
+	now := time.Now()
+	entries := make([]OutboxEntry, 0)
+	for _, queued := range store.state.outbox {
+		if len(entries) >= limit {
+			break
+		}
+		if queued.status == OutboxPending && !queued.nextAttemptAt.After(now) {
+			entries = append(entries, queued.entry)
+		}
+	}
+	return entries, nil
+}
+
+func (store *MemoryStore) MarkOutboxSent(entry OutboxEntry) error {
+	store.state.mu.Lock()
+	defer store.state.mu.Unlock()
+
+	if IsEventKind(entry.Kind) {
+		store.state.outbox = slices.DeleteFunc(store.state.outbox, func(queued *memoryOutboxEntry) bool {
+			return queued.entry.ID == entry.ID
+		})
+		switch entry.Kind {
+		case EventUpdated:
+			store.archiveNotice(entry)
+		case EventDeleted:
+			store.state.archive = slices.DeleteFunc(store.state.archive, func(archived ArchivedNotice) bool {
+				return archived.Topic == entry.Topic && archived.ArticleId == entry.ArticleId
+			})
+		}
+		return nil
+	}
+
+	queued := store.find(entry.ID)
+	if queued != nil {
+		queued.status = OutboxSent
+		queued.entry.Attempts++
+		queued.lastError = ""
+	}
+	store.saveSeen(entry.Topic, entry.Kind, entry.ArticleId, entry.Fingerprint)
+	store.archiveNotice(entry)
+
+	key := cursorKey(entry.Topic, entry.Kind)
+	if entry.Kind == "num" {
+		store.state.cursors[key] = max(store.state.cursors[key], entry.Cursor)
+	} else {
+		store.state.cursors[key] = entry.Cursor
+	}
+	return nil
+}
+
+func (store *MemoryStore) MarkOutboxFailed(entry OutboxEntry, sendErr error, nextAttemptAt time.Time, dead bool) error {
+	store.state.mu.Lock()
+	defer store.state.mu.Unlock()
+
+	queued := store.find(entry.ID)
+	if queued == nil {
+		return nil
+	}
+	queued.status = OutboxPending
+	if dead {
+		queued.status = OutboxDead
+	}
+	queued.entry.Attempts++
+	queued.nextAttemptAt = nextAttemptAt
+	queued.lastError = sendErr.Error()
+	return nil
+}
+
+// 이미 보관된 공지는 처음 전송한 시각을 두고 내용만 바꾼다.
+func (store *MemoryStore) archiveNotice(entry OutboxEntry) {
+	for i := range store.state.archive {
+		archived := &store.state.archive[i]
+		if archived.Topic == entry.Topic && archived.ArticleId == entry.ArticleId {
+			archived.Notice = entry.Notice
+			return
+		}
+	}
+	store.state.archive = append(store.state.archive, ArchivedNotice{
+		Topic:       entry.Topic,
+		ArticleId:   entry.ArticleId,
+		DeliveredAt: time.Now(),
+		Notice:      entry.Notice,
+	})
+}
+
+func (store *MemoryStore) LoadArchivedNotices(topics []string, limit int) ([]ArchivedNotice, error) {
+	store.state.mu.Lock()
+	defer store.state.mu.Unlock()
+
+	notices := make([]ArchivedNotice, 0)
+	for i := len(store.state.archive) - 1; i >= 0 && len(notices) < limit; i-- {
+		if slices.Contains(topics, store.state.archive[i].Topic) {
+			notices = append(notices, store.state.archive[i])
+		}
+	}
+	return notices, nil
+}
+
+func (store *MemoryStore) LoadPinnedNotices(topic string) (map[string]Notice, error) {
+	store.state.mu.Lock()
+	defer store.state.mu.Unlock()
+	return maps.Clone(store.state.pinned[topic]), nil
+}
+
+func (store *MemoryStore) SavePinnedNotices(topic string, notices map[string]Notice) error {
+	store.state.mu.Lock()
+	defer store.state.mu.Unlock()
+	store.state.pinned[topic] = maps.Clone(notices)
+	return nil
+}
+
+func (store *MemoryStore) find(id int64) *memoryOutboxEntry {
+	for _, queued := range store.state.outbox {
+		if queued.entry.ID == id {
+			return queued
+		}
+	}
+	return nil
+}
+
+func cursorKey(topic, kind string) string {
+	return topic + ":" + kind
+}
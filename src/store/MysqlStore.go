@@ -0,0 +1,108 @@
+package store
+
+import (
+	"database/sql"
+	"os"
+
+	. "Notifier/src/utils"
+	"github.com/go-sql-driver/mysql"
+)
+
+// 백엔드와 같은 MySQL을 쓴다. box/num 값은 백엔드의 notice/topic 테이블에 있다.
+// notice 행이 없는 topic은 topic 테이블에 있을 때만 행을 새로 만든다.
+type MysqlStore struct {
+	*sqlStore
+}
+
+// 이미 전송한 공지를 (topic, kind, articleId) 단위로 기록하는 테이블. fingerprint는 마지막으로 보낸 글의 지문이다.
+const mysqlSeenNoticeTableQuery = `CREATE TABLE IF NOT EXISTS crawled_notice (
+	topic VARCHAR(100) NOT NULL,
+	kind VARCHAR(10) NOT NULL,
+	article_id VARCHAR(255) NOT NULL,
+	fingerprint CHAR(64) NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (topic, kind, article_id)
+)`
+
+// 스크랩한 공지를 sink별 전송이 확인될 때까지 보관하는 테이블
+const mysqlOutboxTableQuery = `CREATE TABLE IF NOT EXISTS notice_outbox (
+	id BIGINT NOT NULL AUTO_INCREMENT,
+	topic VARCHAR(100) NOT NULL,
+	kind VARCHAR(10) NOT NULL,
+	article_id VARCHAR(255) NOT NULL,
+	sink VARCHAR(50) NOT NULL DEFAULT 'backend',
+	fingerprint CHAR(64) NOT NULL,
+	cursor_value INT NOT NULL,
+	payload MEDIUMTEXT NOT NULL,
+	status VARCHAR(10) NOT NULL DEFAULT 'pending',
+	attempts INT NOT NULL DEFAULT 0,
+	next_attempt_at DATETIME NOT NULL,
+	last_error TEXT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	PRIMARY KEY (id),
+	UNIQUE KEY uk_notice_outbox (topic, kind, article_id, sink, fingerprint),
+	KEY idx_notice_outbox_due (status, next_attempt_at)
+)`
+
+// 한 sink에라도 전송된 공지를 피드용으로 보관하는 테이블
+const mysqlArchiveTableQuery = `CREATE TABLE IF NOT EXISTS notice_archive (
+	id BIGINT NOT NULL AUTO_INCREMENT,
+	topic VARCHAR(100) NOT NULL,
+	article_id VARCHAR(255) NOT NULL,
+	payload MEDIUMTEXT NOT NULL,
+	delivered_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	PRIMARY KEY (id),
+	UNIQUE KEY uk_notice_archive (topic, article_id),
+	KEY idx_notice_archive_topic (topic, delivered_at)
+)`
+
+// 마지막으로 읽은 목록의 고정 공지. 고정이 풀리거나 삭제된 공지를 찾는 데 쓴다.
+const mysqlPinnedNoticeTableQuery = `CREATE TABLE IF NOT EXISTS pinned_notice (
+	topic VARCHAR(100) NOT NULL,
+	article_id VARCHAR(255) NOT NULL,
+	payload MEDIUMTEXT NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (topic, article_id)
+)`
+
+func (MysqlStore) New(db *sql.DB) *MysqlStore {
+	return &MysqlStore{&sqlStore{
+		db: db,
+		queries: sqlQueries{
+			createTables: []string{mysqlSeenNoticeTableQuery, mysqlOutboxTableQuery, mysqlArchiveTableQuery, mysqlPinnedNoticeTableQuery},
+			loadCursor:   "SELECT n.value FROM notice AS n JOIN topic AS t ON n.topic_id = t.id WHERE t.department = ? AND n.type = ?",
+			saveCursor:   "UPDATE notice AS n JOIN topic AS t ON n.topic_id = t.id SET n.value = ? WHERE t.department = ? AND n.type = ?",
+			insertCursor: "INSERT INTO notice (topic_id, value, type) SELECT t.id, ?, ? FROM topic AS t WHERE t.department = ?",
+			advanceNum:   "UPDATE notice AS n JOIN topic AS t ON n.topic_id = t.id SET n.value = GREATEST(n.value, ?) WHERE t.department = ? AND n.type = ?",
+			saveSeen:     "INSERT INTO crawled_notice (topic, kind, article_id, fingerprint) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE fingerprint = VALUES(fingerprint)",
+			enqueue:      "INSERT IGNORE INTO notice_outbox (topic, kind, article_id, sink, fingerprint, cursor_value, payload, status, next_attempt_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			archive:      "INSERT INTO notice_archive (topic, article_id, payload, delivered_at) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE payload = VALUES(payload)",
+		},
+	}}
+}
+
+func ConnectDB() *sql.DB {
+	config := mysql.Config{
+		User:                 os.Getenv("DB_USER"),
+		Passwd:               os.Getenv("DB_PW"),
+		Net:                  "tcp",
+		Addr:                 os.Getenv("DB_IP") + ":" + os.Getenv("DB_PORT"),
+		DBName:               os.Getenv("DB_NAME"),
+		AllowNativePasswords: true,
+		ParseTime:            true,
+		// 값이 같은 UPDATE도 바꾼 행으로 세어 notice 행이 없는 경우와 구분한다.
+		ClientFoundRows: true,
+	}
+	connector, err := mysql.NewConnector(&config)
+	if err != nil {
+		LogPanic("Failed to create DB connector", err)
+	}
+	db := sql.OpenDB(connector)
+	err = db.Ping()
+	if err != nil {
+		LogPanic("Failed to connect DB", err)
+	}
+	return db
+}
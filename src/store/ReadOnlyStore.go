@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	. "Notifier/models"
+)
+
+// 읽기는 감싼 저장소에 맡기고 쓰기는 모두 버린다. dry-run처럼 상태를 바꾸면 안 되는 실행에 쓴다.
+type ReadOnlyStore struct {
+	inner StateStore
+}
+
+func (ReadOnlyStore) New(inner StateStore) *ReadOnlyStore {
+	return &ReadOnlyStore{inner: inner}
+}
+
+func (store *ReadOnlyStore) Init() error {
+	return nil
+}
+
+func (store *ReadOnlyStore) Ping(ctx context.Context) error {
+	return store.inner.Ping(ctx)
+}
+
+func (store *ReadOnlyStore) Close() error {
+	return store.inner.Close()
+}
+
+func (store *ReadOnlyStore) LoadCursors(topic string) (int, int, error) {
+	return store.inner.LoadCursors(topic)
+}
+
+func (store *ReadOnlyStore) SaveCursor(_, _ string, _ int) error {
+	return nil
+}
+
+func (store *ReadOnlyStore) LoadSeenNotices(topic string) (map[string]string, error) {
+	return store.inner.LoadSeenNotices(topic)
+}
+
+func (store *ReadOnlyStore) SaveSeenNotice(_, _, _, _ string) error {
+	return nil
+}
+
+func (store *ReadOnlyStore) ClearSeenNotices(_ string) error {
+	return nil
+}
+
+func (store *ReadOnlyStore) EnqueueNotices(_ []OutboxEntry) error {
+	return nil
+}
+
+func (store *ReadOnlyStore) LoadDueOutboxEntries(limit int) ([]OutboxEntry, error) {
+	return store.inner.LoadDueOutboxEntries(limit)
+}
+
+func (store *ReadOnlyStore) MarkOutboxSent(_ OutboxEntry) error {
+	return nil
+}
+
+func (store *ReadOnlyStore) MarkOutboxFailed(_ OutboxEntry, _ error, _ time.Time, _ bool) error {
+	return nil
+}
+
+func (store *ReadOnlyStore) LoadArchivedNotices(topics []string, limit int) ([]ArchivedNotice, error) {
+	return store.inner.LoadArchivedNotices(topics, limit)
+}
+
+func (store *ReadOnlyStore) LoadPinnedNotices(topic string) (map[string]Notice, error) {
+	return store.inner.LoadPinnedNotices(topic)
+}
+
+func (store *ReadOnlyStore) SavePinnedNotices(_ string, _ map[string]Notice) error {
+	return nil
+}
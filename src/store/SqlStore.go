@@ -0,0 +1,345 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	. "Notifier/models"
+)
+
+// MySQL과 SQLite가 함께 쓰는 구현. 문법이 다른 쿼리만 sqlQueries로 나눈다.
+type sqlStore struct {
+	db      *sql.DB
+	queries sqlQueries
+}
+
+// 인자 순서는 방언에 관계없이 같다.
+type sqlQueries struct {
+	createTables []string
+	loadCursor   string // (topic, kind)
+	saveCursor   string // (value, topic, kind)
+	insertCursor string // (value, kind, topic) saveCursor가 바꾼 행이 없을 때만 쓴다. 비어 있으면 saveCursor가 행을 만든다.
+	advanceNum   string // (value, topic, kind) 더 큰 값으로만 바꾼다.
+	saveSeen     string // (topic, kind, articleId, fingerprint) 이미 있으면 fingerprint만 바꾼다.
+	enqueue      string // (topic, kind, articleId, sink, fingerprint, cursor, payload, status, nextAttemptAt)
+	archive      string // (topic, articleId, payload, deliveredAt) 이미 있으면 payload만 바꾼다.
+}
+
+func (store *sqlStore) Init() error {
+	for _, query := range store.queries.createTables {
+		_, err := store.db.Exec(query)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (store *sqlStore) Ping(ctx context.Context) error {
+	return store.db.PingContext(ctx)
+}
+
+func (store *sqlStore) Close() error {
+	return store.db.Close()
+}
+
+func (store *sqlStore) LoadCursors(topic string) (int, int, error) {
+	var boxCount, maxNum int
+	err := store.db.QueryRow(store.queries.loadCursor, topic, "box").Scan(&boxCount)
+	if err == nil {
+		err = store.db.QueryRow(store.queries.loadCursor, topic, "num").Scan(&maxNum)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, fmt.Errorf("%w: %s", ErrTopicNotFound, topic)
+	}
+	return boxCount, maxNum, err
+}
+
+func (store *sqlStore) SaveCursor(topic, kind string, value int) error {
+	result, err := store.db.Exec(store.queries.saveCursor, value, topic, kind)
+	if err != nil || store.queries.insertCursor == "" {
+		return err
+	}
+	if updated, err := result.RowsAffected(); err != nil || updated > 0 {
+		return err
+	}
+
+	result, err = store.db.Exec(store.queries.insertCursor, value, kind, topic)
+	if err != nil {
+		return err
+	}
+	if inserted, err := result.RowsAffected(); err != nil || inserted > 0 {
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrTopicNotFound, topic)
+}
+
+// 같은 식별자가 여러 번 있으면 나중에 넣은 대기열 항목의 지문을 쓴다.
+func (store *sqlStore) LoadSeenNotices(topic string) (map[string]string, error) {
+	query := "SELECT kind, article_id, fingerprint FROM (" +
+		"SELECT 0 AS source, 0 AS id, kind, article_id, fingerprint FROM crawled_notice WHERE topic = ? " +
+		"UNION ALL SELECT 1, id, kind, article_id, fingerprint FROM notice_outbox WHERE topic = ?) AS seen ORDER BY source, id"
+	rows, err := store.db.Query(query, topic, topic)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := make(map[string]string)
+	for rows.Next() {
+		var kind, articleId, fingerprint string
+		err = rows.Scan(&kind, &articleId, &fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		if IsEventKind(kind) {
+			continue
+		}
+		seen[SeenNoticeKey(kind, articleId)] = fingerprint
+	}
+	return seen, rows.Err()
+}
+
+func (store *sqlStore) SaveSeenNotice(topic, kind, articleId, fingerprint string) error {
+	_, err := store.db.Exec(store.queries.saveSeen, topic, kind, articleId, fingerprint)
+	return err
+}
+
+func (store *sqlStore) ClearSeenNotices(topic string) error {
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM crawled_notice WHERE topic = ?", topic)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM notice_outbox WHERE topic = ? AND kind IN ('box', 'num') AND status <> ?", topic, OutboxPending)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (store *sqlStore) EnqueueNotices(entries []OutboxEntry) error {
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	for _, entry := range entries {
+		payload, err := encodeOutboxPayload(entry)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(store.queries.enqueue, entry.Topic, entry.Kind, entry.ArticleId, entry.Sink, entry.Fingerprint, entry.Cursor, payload, OutboxPending, now)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// 읽을 수 없는 항목은 dead로 바꾸고 건너뛴다. 남겨 두면 id 순으로 읽을 때마다 뒤의 항목을 막는다.
+func (store *sqlStore) LoadDueOutboxEntries(limit int) ([]OutboxEntry, error) {
+	query := "SELECT id, topic, kind, article_id, sink, fingerprint, cursor_value, attempts, payload FROM notice_outbox WHERE status = ? AND next_attempt_at <= ? ORDER BY id LIMIT ?"
+	rows, err := store.db.Query(query, OutboxPending, time.Now().UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]OutboxEntry, 0)
+	unreadable := make(map[int64]error)
+	for rows.Next() {
+		var entry OutboxEntry
+		var payload []byte
+		err = rows.Scan(&entry.ID, &entry.Topic, &entry.Kind, &entry.ArticleId, &entry.Sink, &entry.Fingerprint, &entry.Cursor, &entry.Attempts, &payload)
+		if err != nil {
+			return entries, err
+		}
+		err = decodeOutboxPayload(&entry, payload)
+		if err != nil {
+			unreadable[entry.ID] = fmt.Errorf("unreadable payload: %w", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	err = rows.Err()
+	if err != nil {
+		return entries, err
+	}
+	// SQLite는 연결이 하나뿐이라 rows를 닫은 뒤에 바꾼다.
+	rows.Close()
+
+	for id, decodeErr := range unreadable {
+		err = store.MarkOutboxFailed(OutboxEntry{ID: id}, decodeErr, time.Now(), true)
+		if err != nil {
+			return entries, fmt.Errorf("outbox entry %d: %w", id, err)
+		}
+	}
+	return entries, nil
+}
+
+func (store *sqlStore) MarkOutboxSent(entry OutboxEntry) error {
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if IsEventKind(entry.Kind) {
+		_, err = tx.Exec("DELETE FROM notice_outbox WHERE id = ?", entry.ID)
+		if err != nil {
+			return err
+		}
+		switch entry.Kind {
+		case EventUpdated:
+			err = store.archive(tx, entry)
+		case EventDeleted:
+			_, err = tx.Exec("DELETE FROM notice_archive WHERE topic = ? AND article_id = ?", entry.Topic, entry.ArticleId)
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	_, err = tx.Exec("UPDATE notice_outbox SET status = ?, attempts = attempts + 1, last_error = NULL WHERE id = ?", OutboxSent, entry.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(store.queries.saveSeen, entry.Topic, entry.Kind, entry.ArticleId, entry.Fingerprint)
+	if err != nil {
+		return err
+	}
+
+	err = store.archive(tx, entry)
+	if err != nil {
+		return err
+	}
+
+	query := store.queries.saveCursor
+	if entry.Kind == "num" {
+		query = store.queries.advanceNum
+	}
+	_, err = tx.Exec(query, entry.Cursor, entry.Topic, entry.Kind)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (store *sqlStore) archive(tx *sql.Tx, entry OutboxEntry) error {
+	payload, err := json.Marshal(entry.Notice)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(store.queries.archive, entry.Topic, entry.ArticleId, payload, time.Now().UTC())
+	return err
+}
+
+func (store *sqlStore) MarkOutboxFailed(entry OutboxEntry, sendErr error, nextAttemptAt time.Time, dead bool) error {
+	status := OutboxPending
+	if dead {
+		status = OutboxDead
+	}
+	query := "UPDATE notice_outbox SET status = ?, attempts = attempts + 1, next_attempt_at = ?, last_error = ? WHERE id = ?"
+	_, err := store.db.Exec(query, status, nextAttemptAt.UTC(), sendErr.Error(), entry.ID)
+	return err
+}
+
+// 읽을 수 없는 항목은 건너뛰지 않고 에러를 반환한다.
+func (store *sqlStore) LoadArchivedNotices(topics []string, limit int) ([]ArchivedNotice, error) {
+	notices := make([]ArchivedNotice, 0)
+	if len(topics) == 0 {
+		return notices, nil
+	}
+	args := make([]any, 0, len(topics)+1)
+	for _, topic := range topics {
+		args = append(args, topic)
+	}
+	args = append(args, limit)
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(topics)), ", ")
+	query := "SELECT topic, article_id, payload, delivered_at FROM notice_archive WHERE topic IN (" + placeholders + ") ORDER BY delivered_at DESC, id DESC LIMIT ?"
+	rows, err := store.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var notice ArchivedNotice
+		var payload []byte
+		err = rows.Scan(&notice.Topic, &notice.ArticleId, &payload, &notice.DeliveredAt)
+		if err != nil {
+			return notices, err
+		}
+		err = json.Unmarshal(payload, &notice.Notice)
+		if err != nil {
+			return notices, fmt.Errorf("archived notice %s/%s: %w", notice.Topic, notice.ArticleId, err)
+		}
+		notices = append(notices, notice)
+	}
+	return notices, rows.Err()
+}
+
+func (store *sqlStore) LoadPinnedNotices(topic string) (map[string]Notice, error) {
+	rows, err := store.db.Query("SELECT article_id, payload FROM pinned_notice WHERE topic = ?", topic)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notices := make(map[string]Notice)
+	for rows.Next() {
+		var articleId string
+		var payload []byte
+		err = rows.Scan(&articleId, &payload)
+		if err != nil {
+			return nil, err
+		}
+		var notice Notice
+		err = json.Unmarshal(payload, &notice)
+		if err != nil {
+			return nil, fmt.Errorf("pinned notice %s/%s: %w", topic, articleId, err)
+		}
+		notices[articleId] = notice
+	}
+	return notices, rows.Err()
+}
+
+func (store *sqlStore) SavePinnedNotices(topic string, notices map[string]Notice) error {
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM pinned_notice WHERE topic = ?", topic)
+	if err != nil {
+		return err
+	}
+	for articleId, notice := range notices {
+		payload, err := json.Marshal(notice)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec("INSERT INTO pinned_notice (topic, article_id, payload) VALUES (?, ?, ?)", topic, articleId, payload)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+
+	. "Notifier/src/utils"
+	_ "modernc.org/sqlite"
+)
+
+// 백엔드 DB 없이 단독으로 실행할 때 쓰는 파일 하나짜리 저장소. box/num 값은 crawl_cursor 테이블에 둔다.
+type SqliteStore struct {
+	*sqlStore
+}
+
+const sqliteCursorTableQuery = `CREATE TABLE IF NOT EXISTS crawl_cursor (
+	topic TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	value INTEGER NOT NULL,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (topic, kind)
+)`
+
+const sqliteSeenNoticeTableQuery = `CREATE TABLE IF NOT EXISTS crawled_notice (
+	topic TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	article_id TEXT NOT NULL,
+	fingerprint TEXT NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (topic, kind, article_id)
+)`
+
+const sqliteOutboxTableQuery = `CREATE TABLE IF NOT EXISTS notice_outbox (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	topic TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	article_id TEXT NOT NULL,
+	sink TEXT NOT NULL DEFAULT 'backend',
+	fingerprint TEXT NOT NULL,
+	cursor_value INTEGER NOT NULL,
+	payload TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'pending',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	next_attempt_at DATETIME NOT NULL,
+	last_error TEXT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (topic, kind, article_id, sink, fingerprint)
+)`
+
+const sqliteOutboxIndexQuery = "CREATE INDEX IF NOT EXISTS idx_notice_outbox_due ON notice_outbox (status, next_attempt_at)"
+
+const sqliteArchiveTableQuery = `CREATE TABLE IF NOT EXISTS notice_archive (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	topic TEXT NOT NULL,
+	article_id TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	delivered_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (topic, article_id)
+)`
+
+const sqliteArchiveIndexQuery = "CREATE INDEX IF NOT EXISTS idx_notice_archive_topic ON notice_archive (topic, delivered_at)"
+
+const sqlitePinnedNoticeTableQuery = `CREATE TABLE IF NOT EXISTS pinned_notice (
+	topic TEXT NOT NULL,
+	article_id TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (topic, article_id)
+)`
+
+func (SqliteStore) New(path string) *SqliteStore {
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		LogPanic("Failed to create SQLite directory", err, "path", path)
+	}
+	// 시각은 UTC로 저장해 문자열 비교로도 순서가 맞게 한다.
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_time_format=sqlite")
+	if err != nil {
+		LogPanic("Failed to open SQLite database", err, "path", path)
+	}
+	// 쓰기 잠금 경합을 피하려고 연결 하나만 쓴다.
+	db.SetMaxOpenConns(1)
+
+	return &SqliteStore{&sqlStore{
+		db: db,
+		queries: sqlQueries{
+			createTables: []string{sqliteCursorTableQuery, sqliteSeenNoticeTableQuery, sqliteOutboxTableQuery, sqliteOutboxIndexQuery, sqliteArchiveTableQuery, sqliteArchiveIndexQuery, sqlitePinnedNoticeTableQuery},
+			loadCursor:   "SELECT value FROM crawl_cursor WHERE topic = ? AND kind = ?",
+			saveCursor:   "INSERT INTO crawl_cursor (value, topic, kind) VALUES (?, ?, ?) ON CONFLICT (topic, kind) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP",
+			advanceNum:   "INSERT INTO crawl_cursor (value, topic, kind) VALUES (?, ?, ?) ON CONFLICT (topic, kind) DO UPDATE SET value = MAX(value, excluded.value), updated_at = CURRENT_TIMESTAMP",
+			saveSeen:     "INSERT INTO crawled_notice (topic, kind, article_id, fingerprint) VALUES (?, ?, ?, ?) ON CONFLICT (topic, kind, article_id) DO UPDATE SET fingerprint = excluded.fingerprint",
+			enqueue:      "INSERT OR IGNORE INTO notice_outbox (topic, kind, article_id, sink, fingerprint, cursor_value, payload, status, next_attempt_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			archive:      "INSERT INTO notice_archive (topic, article_id, payload, delivered_at) VALUES (?, ?, ?, ?) ON CONFLICT (topic, article_id) DO UPDATE SET payload = excluded.payload, updated_at = CURRENT_TIMESTAMP",
+		},
+	}}
+}
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	. "Notifier/models"
+	. "Notifier/src/utils"
+)
+
+const (
+	OutboxPending = "pending"
+	OutboxSent    = "sent"
+	OutboxDead    = "dead"
+)
+
+// 이미 보낸 공지의 이벤트. OutboxEntry.Kind로 쓴다.
+const (
+	EventUpdated  = "updated"
+	EventUnpinned = "unpinned"
+	EventDeleted  = "deleted"
+)
+
+// topic의 box/num 값이 저장되어 있지 않을 때 반환하는 에러
+var ErrTopicNotFound = errors.New("topic not found in state store")
+
+// 크롤링 상태(box/num 값, 전송 기록, 전송 대기열)를 보관하는 저장소
+type StateStore interface {
+	// 필요한 테이블을 만든다.
+	Init() error
+	Ping(ctx context.Context) error
+	Close() error
+
+	// topic의 BoxCount, MaxNum을 불러온다. 없으면 ErrTopicNotFound를 반환한다.
+	LoadCursors(topic string) (int, int, error)
+	// kind는 box 또는 num이다.
+	SaveCursor(topic, kind string, value int) error
+
+	// 이미 전송했거나 전송 대기 중인 공지의 식별자(kind:articleId)별 지문을 불러온다. 지문을 모르면 빈 문자열이다.
+	LoadSeenNotices(topic string) (map[string]string, error)
+	SaveSeenNotice(topic, kind, articleId, fingerprint string) error
+	// topic의 전송 기록을 지운다. 전송 대기 중인 공지는 남기고, 보냈거나 포기한 box/num 항목은 대기열에서도 지운다.
+	ClearSeenNotices(topic string) error
+
+	// 공지 하나의 sink별 항목을 한 번에 넣는다. 같은 (topic, kind, articleId, sink, fingerprint)가 이미 들어 있으면 무시한다.
+	// 보낸 이벤트 항목은 MarkOutboxSent에서 지우므로, 이벤트는 아직 보내지 않은 항목과만 겹치지 않게 한다.
+	EnqueueNotices(entries []OutboxEntry) error
+	LoadDueOutboxEntries(limit int) ([]OutboxEntry, error)
+	// 전송이 확인된 공지를 전송 기록과 보관함에 옮기고 box/num 값을 전진시킨다.
+	// 이벤트는 전송 기록과 box/num 값을 건드리지 않는다. updated이면 보관함의 공지를 바꾸고, deleted이면 보관함에서 뺀다.
+	// 보낸 이벤트 항목은 대기열에서 지워 A→B→A처럼 이전 내용으로 되돌아간 수정도 다시 보낼 수 있게 한다.
+	MarkOutboxSent(entry OutboxEntry) error
+	// 전송 실패를 기록한다. dead이면 더 이상 재시도하지 않는다.
+	MarkOutboxFailed(entry OutboxEntry, sendErr error, nextAttemptAt time.Time, dead bool) error
+
+	// topics의 보관된 공지를 처음 전송한 시각의 역순으로 limit개까지 불러온다.
+	LoadArchivedNotices(topics []string, limit int) ([]ArchivedNotice, error)
+
+	// 마지막으로 읽은 목록의 고정 공지를 게시글 번호별로 불러온다.
+	LoadPinnedNotices(topic string) (map[string]Notice, error)
+	// topic의 고정 공지를 notices로 바꾼다.
+	SavePinnedNotices(topic string, notices map[string]Notice) error
+}
+
+// 공용 상태 저장소. main에서 STATE_STORE 설정으로 다시 만든다.
+var Store StateStore = MemoryStore{}.New()
+
+// STATE_STORE 환경변수(mysql, sqlite, memory)로 저장소를 고른다. 기본값은 mysql이다.
+func OpenStateStore() StateStore {
+	var stateStore StateStore
+	kind := os.Getenv("STATE_STORE")
+	switch kind {
+	case "", "mysql":
+		stateStore = MysqlStore{}.New(ConnectDB())
+	case "sqlite":
+		path := os.Getenv("SQLITE_PATH")
+		if path == "" {
+			path = "data/notifier.db"
+		}
+		stateStore = SqliteStore{}.New(path)
+	case "memory":
+		stateStore = MemoryStore{}.New()
+	default:
+		LogPanic("Unknown STATE_STORE", errors.New(kind))
+	}
+
+	err := stateStore.Init()
+	if err != nil {
+		LogPanic("Failed to initialize state store", err, "store", kind)
+	}
+	return stateStore
+}
+
+func SeenNoticeKey(kind, articleId string) string {
+	return kind + ":" + articleId
+}
+
+// box, num이 아닌 항목은 이미 보낸 공지의 이벤트다.
+func IsEventKind(kind string) bool {
+	return kind != "box" && kind != "num"
+}
+
+// 새 공지는 Notice를, 이벤트는 NoticeEvent를 payload로 저장한다.
+func encodeOutboxPayload(entry OutboxEntry) ([]byte, error) {
+	if IsEventKind(entry.Kind) {
+		return json.Marshal(NoticeEvent{Event: entry.Kind, Notice: entry.Notice, Changes: entry.Changes})
+	}
+	return json.Marshal(entry.Notice)
+}
+
+func decodeOutboxPayload(entry *OutboxEntry, payload []byte) error {
+	if !IsEventKind(entry.Kind) {
+		return json.Unmarshal(payload, &entry.Notice)
+	}
+	var event NoticeEvent
+	err := json.Unmarshal(payload, &event)
+	if err != nil {
+		return err
+	}
+	entry.Notice = event.Notice
+	entry.Changes = event.Changes
+	return nil
+}
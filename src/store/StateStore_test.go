@@ -0,0 +1,221 @@
+package store
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "Notifier/models"
+)
+
+// MySQL을 제외한 구현이 같은 규칙을 따르는지 확인한다.
+func TestStateStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) StateStore{
+		"memory": func(_ *testing.T) StateStore {
+			return MemoryStore{}.New()
+		},
+		"sqlite": func(t *testing.T) StateStore {
+			return SqliteStore{}.New(filepath.Join(t.TempDir(), "notifier.db"))
+		},
+	}
+
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			stateStore := open(t)
+			defer stateStore.Close()
+			err := stateStore.Init()
+			if err != nil {
+				t.Fatal(err)
+			}
+			testCursors(t, stateStore)
+			testOutbox(t, stateStore)
+			testEvents(t, stateStore)
+			testPinned(t, stateStore)
+		})
+	}
+}
+
+func testCursors(t *testing.T, stateStore StateStore) {
+	_, _, err := stateStore.LoadCursors("Cursor")
+	if !errors.Is(err, ErrTopicNotFound) {
+		t.Fatalf("LoadCursors on an unknown topic returned %v, want ErrTopicNotFound", err)
+	}
+
+	mustSucceed(t, stateStore.SaveCursor("Cursor", "box", 3))
+	mustSucceed(t, stateStore.SaveCursor("Cursor", "num", 120))
+	boxCount, maxNum, err := stateStore.LoadCursors("Cursor")
+	if err != nil || boxCount != 3 || maxNum != 120 {
+		t.Errorf("LoadCursors = (%d, %d, %v), want (3, 120, nil)", boxCount, maxNum, err)
+	}
+}
+
+func testOutbox(t *testing.T, stateStore StateStore) {
+	mustSucceed(t, stateStore.SaveCursor("Outbox", "box", 2))
+	mustSucceed(t, stateStore.SaveCursor("Outbox", "num", 100))
+	mustSucceed(t, stateStore.SaveSeenNotice("Outbox", "num", "1", "old"))
+	// 번호가 다시 쓰여 다른 글을 보내면 지문만 바뀐다.
+	mustSucceed(t, stateStore.SaveSeenNotice("Outbox", "num", "1", "new"))
+
+	entries := []OutboxEntry{
+		{Topic: "Outbox", Kind: "num", ArticleId: "102", Sink: "backend", Cursor: 102, Notice: Notice{ID: "102", Title: "newer"}},
+		{Topic: "Outbox", Kind: "num", ArticleId: "101", Sink: "backend", Cursor: 101, Notice: Notice{ID: "101", Title: "older"}},
+		{Topic: "Outbox", Kind: "box", ArticleId: "7", Sink: "backend", Fingerprint: "p", Cursor: 1, Notice: Notice{ID: "공지"}},
+		{Topic: "Outbox", Kind: "box", ArticleId: "7", Sink: "slack", Fingerprint: "p", Cursor: 1, Notice: Notice{ID: "공지"}},
+	}
+	mustSucceed(t, stateStore.EnqueueNotices(entries))
+	// 같은 공지를 다시 넣어도 무시된다.
+	mustSucceed(t, stateStore.EnqueueNotices(entries[:1]))
+
+	seen, err := stateStore.LoadSeenNotices("Outbox")
+	mustSucceed(t, err)
+	for _, key := range []string{"num:1", "num:101", "num:102", "box:7"} {
+		if _, ok := seen[key]; !ok {
+			t.Errorf("seen notices missing %q: %v", key, seen)
+		}
+	}
+	if seen["num:1"] != "new" || seen["box:7"] != "p" {
+		t.Errorf("seen fingerprints = %v, want num:1 new and box:7 p", seen)
+	}
+
+	due, err := stateStore.LoadDueOutboxEntries(10)
+	mustSucceed(t, err)
+	if len(due) != 4 || due[0].Notice.Title != "newer" || due[3].Sink != "slack" {
+		t.Fatalf("due entries = %+v, want the four queued entries in order", due)
+	}
+
+	// 실패한 공지는 재시도 시각 전까지 다시 나오지 않는다.
+	mustSucceed(t, stateStore.MarkOutboxFailed(due[2], errors.New("boom"), time.Now().Add(time.Hour), false))
+	mustSucceed(t, stateStore.MarkOutboxFailed(due[3], errors.New("boom"), time.Now().Add(time.Hour), true))
+	// 늦게 전송된 작은 번호가 num 값을 되돌리지 않는다.
+	mustSucceed(t, stateStore.MarkOutboxSent(due[0]))
+	mustSucceed(t, stateStore.MarkOutboxSent(due[1]))
+
+	due, err = stateStore.LoadDueOutboxEntries(10)
+	mustSucceed(t, err)
+	if len(due) != 0 {
+		t.Errorf("due entries after delivery = %+v, want none", due)
+	}
+
+	boxCount, maxNum, err := stateStore.LoadCursors("Outbox")
+	if err != nil || boxCount != 2 || maxNum != 102 {
+		t.Errorf("LoadCursors = (%d, %d, %v), want (2, 102, nil)", boxCount, maxNum, err)
+	}
+
+	// 전송된 공지만 최근에 전송한 순서로 보관함에 남는다.
+	archived, err := stateStore.LoadArchivedNotices([]string{"Outbox", "Cursor"}, 10)
+	mustSucceed(t, err)
+	if len(archived) != 2 || archived[0].ArticleId != "101" || archived[1].Notice.Title != "newer" || archived[0].DeliveredAt.IsZero() {
+		t.Errorf("archived notices = %+v, want 101 then 102", archived)
+	}
+	archived, err = stateStore.LoadArchivedNotices([]string{"Cursor"}, 10)
+	mustSucceed(t, err)
+	if len(archived) != 0 {
+		t.Errorf("archived notices of another topic = %+v, want none", archived)
+	}
+
+	// 전송 기록을 지우면 재시도를 기다리는 공지만 남고, 보낸 공지는 다시 넣을 수 있다.
+	mustSucceed(t, stateStore.ClearSeenNotices("Outbox"))
+	seen, err = stateStore.LoadSeenNotices("Outbox")
+	mustSucceed(t, err)
+	if len(seen) != 1 || seen["box:7"] != "p" {
+		t.Errorf("seen notices after clear = %v, want only the pending box:7", seen)
+	}
+	mustSucceed(t, stateStore.EnqueueNotices(entries[:1]))
+	due, err = stateStore.LoadDueOutboxEntries(10)
+	mustSucceed(t, err)
+	if len(due) != 1 || due[0].ArticleId != "102" {
+		t.Fatalf("due entries after clear = %+v, want 102 queued again", due)
+	}
+	mustSucceed(t, stateStore.MarkOutboxSent(due[0]))
+}
+
+func testEvents(t *testing.T, stateStore StateStore) {
+	mustSucceed(t, stateStore.SaveCursor("Events", "box", 0))
+	mustSucceed(t, stateStore.SaveCursor("Events", "num", 4))
+	mustSucceed(t, stateStore.EnqueueNotices([]OutboxEntry{{Topic: "Events", Kind: "num", ArticleId: "5", Sink: "backend", Cursor: 5, Notice: Notice{ID: "5", Title: "v1"}}}))
+	due, err := stateStore.LoadDueOutboxEntries(10)
+	mustSucceed(t, err)
+	mustSucceed(t, stateStore.MarkOutboxSent(due[0]))
+
+	updated := func(title string) OutboxEntry {
+		return OutboxEntry{Topic: "Events", Kind: EventUpdated, ArticleId: "5", Sink: "backend", Fingerprint: title, Notice: Notice{ID: "5", Title: title},
+			Changes: []FieldChange{{Field: "title", Old: "v1", New: title}}}
+	}
+	mustSucceed(t, stateStore.EnqueueNotices([]OutboxEntry{updated("v2")}))
+	// 같은 내용의 이벤트는 한 번만, 내용이 또 바뀌면 새 항목으로 들어간다.
+	mustSucceed(t, stateStore.EnqueueNotices([]OutboxEntry{updated("v2")}))
+	mustSucceed(t, stateStore.EnqueueNotices([]OutboxEntry{updated("v3")}))
+
+	seen, err := stateStore.LoadSeenNotices("Events")
+	mustSucceed(t, err)
+	if len(seen) != 1 || !hasKey(seen, "num:5") {
+		t.Errorf("seen notices = %v, want only num:5", seen)
+	}
+
+	due, err = stateStore.LoadDueOutboxEntries(10)
+	mustSucceed(t, err)
+	if len(due) != 2 || due[0].Kind != EventUpdated || due[0].Notice.Title != "v2" || len(due[0].Changes) != 1 || due[0].Changes[0].New != "v2" {
+		t.Fatalf("due events = %+v, want v2 and v3 with their changes", due)
+	}
+	mustSucceed(t, stateStore.MarkOutboxSent(due[0]))
+
+	// 보낸 이벤트와 같은 내용으로 되돌아가면 다시 넣는다. 아직 보내지 않은 v3는 그대로 한 번만 남는다.
+	mustSucceed(t, stateStore.EnqueueNotices([]OutboxEntry{updated("v2"), updated("v3")}))
+	due, err = stateStore.LoadDueOutboxEntries(10)
+	mustSucceed(t, err)
+	if len(due) != 2 || due[0].Notice.Title != "v3" || due[1].Notice.Title != "v2" {
+		t.Fatalf("due events after v2 was sent = %+v, want v3 and v2 again", due)
+	}
+	mustSucceed(t, stateStore.MarkOutboxSent(due[0]))
+	mustSucceed(t, stateStore.MarkOutboxSent(due[1]))
+
+	// 이벤트는 num 값을 바꾸지 않고 보관함의 공지만 바꾼다.
+	_, maxNum, err := stateStore.LoadCursors("Events")
+	if err != nil || maxNum != 5 {
+		t.Errorf("LoadCursors num = (%d, %v), want 5", maxNum, err)
+	}
+	archived, err := stateStore.LoadArchivedNotices([]string{"Events"}, 10)
+	mustSucceed(t, err)
+	if len(archived) != 1 || archived[0].Notice.Title != "v2" {
+		t.Errorf("archived notices = %+v, want 5 with the updated title", archived)
+	}
+
+	// 삭제된 공지는 보관함에서 빠진다.
+	mustSucceed(t, stateStore.EnqueueNotices([]OutboxEntry{{Topic: "Events", Kind: EventDeleted, ArticleId: "5", Sink: "backend", Fingerprint: "d", Notice: Notice{ID: "5"}}}))
+	due, err = stateStore.LoadDueOutboxEntries(10)
+	mustSucceed(t, err)
+	mustSucceed(t, stateStore.MarkOutboxSent(due[len(due)-1]))
+	archived, err = stateStore.LoadArchivedNotices([]string{"Events"}, 10)
+	mustSucceed(t, err)
+	if len(archived) != 0 {
+		t.Errorf("archived notices after deletion = %+v, want none", archived)
+	}
+}
+
+func testPinned(t *testing.T, stateStore StateStore) {
+	pinned, err := stateStore.LoadPinnedNotices("Pinned")
+	if err != nil || len(pinned) != 0 {
+		t.Fatalf("LoadPinnedNotices on an unknown topic = (%v, %v), want nothing", pinned, err)
+	}
+
+	mustSucceed(t, stateStore.SavePinnedNotices("Pinned", map[string]Notice{"1": {Title: "one"}, "2": {Title: "two"}}))
+	mustSucceed(t, stateStore.SavePinnedNotices("Pinned", map[string]Notice{"2": {Title: "two"}, "3": {Title: "three"}}))
+	pinned, err = stateStore.LoadPinnedNotices("Pinned")
+	mustSucceed(t, err)
+	if len(pinned) != 2 || pinned["2"].Title != "two" || pinned["3"].Title != "three" {
+		t.Errorf("pinned notices = %v, want 2 and 3", pinned)
+	}
+}
+
+func hasKey(seen map[string]string, key string) bool {
+	_, ok := seen[key]
+	return ok
+}
+
+func mustSucceed(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
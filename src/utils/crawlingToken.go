@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const crawlingTokenKey = "crawling-token"
+
+// 프로세스 전체가 함께 쓰는 Redis 클라이언트
+var RedisClient = redis.NewClient(&redis.Options{
+	Addr: os.Getenv("REDIS_HOST") + ":" + os.Getenv("REDIS_PORT"),
+})
+
+// 웹훅에 붙이는 crawling-token. CRAWLING_TOKEN_TTL초(기본 300초) 동안 캐시한다.
+var CrawlingToken = TokenSource{}.New(RedisClient, time.Duration(envInt("CRAWLING_TOKEN_TTL", 300))*time.Second)
+
+// Redis의 crawling-token을 읽어 ttl 동안 재사용한다.
+type TokenSource struct {
+	client *redis.Client
+	ttl    time.Duration
+	cache  *tokenCache
+}
+
+type tokenCache struct {
+	mu        sync.Mutex
+	token     string
+	fetchedAt time.Time
+}
+
+func (TokenSource) New(client *redis.Client, ttl time.Duration) *TokenSource {
+	return &TokenSource{client: client, ttl: ttl, cache: &tokenCache{}}
+}
+
+// 캐시가 비었거나 만료되었으면 Redis에서 다시 읽는다.
+func (source *TokenSource) Token(ctx context.Context) (string, error) {
+	source.cache.mu.Lock()
+	defer source.cache.mu.Unlock()
+
+	if source.cache.token != "" && time.Since(source.cache.fetchedAt) < source.ttl {
+		return source.cache.token, nil
+	}
+
+	token, err := source.client.Get(ctx, crawlingTokenKey).Result()
+	if errors.Is(err, redis.Nil) {
+		err = fmt.Errorf("%s not found in Redis", crawlingTokenKey)
+	}
+	if err != nil {
+		RedisTokenErrors.Inc()
+		return "", err
+	}
+	source.cache.token = token
+	source.cache.fetchedAt = time.Now()
+	return token, nil
+}
+
+// 백엔드가 토큰을 거부했을 때 다음 Token 호출에서 Redis를 다시 읽게 한다.
+func (source *TokenSource) Invalidate() {
+	source.cache.mu.Lock()
+	defer source.cache.mu.Unlock()
+	source.cache.token = ""
+}
+
+// Redis 연결 확인
+func PingRedis(ctx context.Context) error {
+	return RedisClient.Ping(ctx).Err()
+}
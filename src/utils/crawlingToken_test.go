@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Notifier/src/signature"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func useMiniredis(t *testing.T) *miniredis.Miniredis {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	previous := CrawlingToken
+	CrawlingToken = TokenSource{}.New(client, time.Hour)
+	t.Cleanup(func() { CrawlingToken = previous })
+	return server
+}
+
+func TestSendCrawlingWebhookRefreshesRejectedToken(t *testing.T) {
+	redisServer := useMiniredis(t)
+	redisServer.Set(crawlingTokenKey, "old")
+
+	var requests atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("crawling-token") != "new" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer backend.Close()
+
+	ctx := context.Background()
+	// 캐시된 토큰은 ttl 동안 Redis를 다시 읽지 않는다.
+	if token, _ := CrawlingToken.Token(ctx); token != "old" {
+		t.Fatalf("token = %q, want old", token)
+	}
+	redisServer.Set(crawlingTokenKey, "new")
+	if token, _ := CrawlingToken.Token(ctx); token != "old" {
+		t.Fatalf("cached token = %q, want old", token)
+	}
+
+	err := SendCrawlingWebhook(ctx, backend.URL, map[string]string{"id": "1"})
+	if err != nil {
+		t.Fatalf("SendCrawlingWebhook failed after token refresh: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("backend received %d requests, want 2 (rejected, then refreshed)", got)
+	}
+}
+
+// 받는 쪽이 서명을 검증해도 새 토큰으로 다시 보낸 요청은 재전송으로 거부되지 않는다.
+func TestSendCrawlingWebhookRetryPassesReplayCheck(t *testing.T) {
+	redisServer := useMiniredis(t)
+	redisServer.Set(crawlingTokenKey, "old")
+	previous := WebhookSecret
+	WebhookSecret = []byte("test-secret")
+	t.Cleanup(func() { WebhookSecret = previous })
+
+	var accepted atomic.Int32
+	verifier := signature.Verifier{}.New(WebhookSecret, 5*time.Minute)
+	backend := httptest.NewServer(verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("crawling-token") != "new" {
+			redisServer.Set(crawlingTokenKey, "new")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		accepted.Add(1)
+	})))
+	defer backend.Close()
+
+	err := SendCrawlingWebhook(context.Background(), backend.URL, map[string]string{"id": "1"})
+	if err != nil {
+		t.Fatalf("SendCrawlingWebhook failed on signed retry: %v", err)
+	}
+	if got := accepted.Load(); got != 1 {
+		t.Errorf("backend accepted %d requests, want 1", got)
+	}
+}
+
+func TestSendCrawlingWebhookReturnsErrorWithoutToken(t *testing.T) {
+	useMiniredis(t)
+
+	var requests atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer backend.Close()
+
+	err := SendCrawlingWebhook(context.Background(), backend.URL, map[string]string{"id": "1"})
+	if err == nil {
+		t.Fatal("expected an error when crawling-token is missing")
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("backend received %d requests without a token", got)
+	}
+}
@@ -0,0 +1,221 @@
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	. "Notifier/models"
+	"golang.org/x/time/rate"
+)
+
+// 목록/상세 페이지 요청에 쓰는 공용 Fetcher. main에서 설정 파일로 다시 만든다.
+var PageFetcher = Fetcher{}.New(FetcherConfig{}, HTTPClient)
+
+// 호스트별 토큰 버킷과 전체 동시 요청 수 제한을 거쳐 요청을 보낸다.
+// GET/HEAD 요청은 네트워크 에러와 429, 5xx 응답에 대해 지수 백오프로 재시도한다.
+type Fetcher struct {
+	client     *http.Client
+	config     FetcherConfig
+	inFlight   chan struct{}
+	limiters   *hostLimiters
+	validators *validatorCache
+}
+
+type hostLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// 조건부 GET에 쓰는 URL별 ETag/Last-Modified
+type validatorCache struct {
+	mu         sync.Mutex
+	validators map[string]validator
+}
+
+type validator struct {
+	etag         string
+	lastModified string
+}
+
+func (Fetcher) New(config FetcherConfig, client *http.Client) *Fetcher {
+	if config.MaxInFlight <= 0 {
+		config.MaxInFlight = 8
+	}
+	if config.DefaultPolicy.DelayMs <= 0 {
+		config.DefaultPolicy.DelayMs = 1000
+	}
+	config.HTTP = withHTTPDefaults(config.HTTP)
+	return &Fetcher{
+		client:     client,
+		config:     config,
+		inFlight:   make(chan struct{}, config.MaxInFlight),
+		limiters:   &hostLimiters{limiters: make(map[string]*rate.Limiter)},
+		validators: &validatorCache{validators: make(map[string]validator)},
+	}
+}
+
+func LoadFetcherConfig(path string) FetcherConfig {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	var config FetcherConfig
+	err = json.NewDecoder(file).Decode(&config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return config
+}
+
+// notifier마다 주기 시작 시각을 흩뜨릴 지연. StartJitterMs보다 짧은 임의의 값이다.
+func (fetcher *Fetcher) StartDelay() time.Duration {
+	jitter := time.Duration(fetcher.config.StartJitterMs) * time.Millisecond
+	if jitter <= 0 {
+		return 0
+	}
+	return rand.N(jitter)
+}
+
+func (fetcher *Fetcher) UserAgent() string {
+	return fetcher.config.HTTP.UserAgent
+}
+
+// 제한을 기다리는 동안 ctx가 취소되면 요청을 보내지 않고 에러를 반환한다.
+// 재시도할 때마다 호스트별 제한을 다시 거친다.
+func (fetcher *Fetcher) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	retryable := req.Method == http.MethodGet || req.Method == http.MethodHead
+
+	for attempt := 0; ; attempt++ {
+		resp, err := fetcher.do(req)
+		if !retryable || attempt >= fetcher.config.HTTP.MaxRetries || !shouldRetry(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		delay := time.Duration(fetcher.config.HTTP.RetryBaseDelayMs) * time.Millisecond << attempt
+		delay += rand.N(delay/2 + 1)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (fetcher *Fetcher) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	host := req.URL.Hostname()
+
+	startedAt := time.Now()
+	err := fetcher.limiter(host).Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case fetcher.inFlight <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-fetcher.inFlight }()
+	FetchWaitDuration.WithLabelValues(host).Observe(time.Since(startedAt).Seconds())
+
+	return fetcher.client.Do(req)
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// 이전 응답의 ETag/Last-Modified를 조건부 요청 헤더로 붙인다.
+func (fetcher *Fetcher) addValidators(req *http.Request) {
+	fetcher.validators.mu.Lock()
+	defer fetcher.validators.mu.Unlock()
+
+	cached, ok := fetcher.validators.validators[req.URL.String()]
+	if !ok {
+		return
+	}
+	if cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+	if cached.lastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.lastModified)
+	}
+}
+
+func (fetcher *Fetcher) saveValidators(url string, resp *http.Response) {
+	cached := validator{
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+	if cached.etag == "" && cached.lastModified == "" {
+		return
+	}
+
+	fetcher.validators.mu.Lock()
+	defer fetcher.validators.mu.Unlock()
+	fetcher.validators.validators[url] = cached
+}
+
+// 페이지 처리에 실패했을 때 다음 요청이 304로 건너뛰지 않도록 저장한 값을 지운다.
+func (fetcher *Fetcher) Forget(url string) {
+	fetcher.validators.mu.Lock()
+	defer fetcher.validators.mu.Unlock()
+	delete(fetcher.validators.validators, url)
+}
+
+func (fetcher *Fetcher) limiter(host string) *rate.Limiter {
+	policy := fetcher.policy(host)
+
+	fetcher.limiters.mu.Lock()
+	defer fetcher.limiters.mu.Unlock()
+
+	limiter, ok := fetcher.limiters.limiters[policy.Host]
+	if !ok {
+		burst := max(policy.Burst, 1)
+		limiter = rate.NewLimiter(rate.Every(time.Duration(policy.DelayMs)*time.Millisecond), burst)
+		fetcher.limiters.limiters[policy.Host] = limiter
+	}
+	return limiter
+}
+
+// 가장 구체적으로 일치하는 정책을 찾는다. 없으면 호스트마다 기본 정책으로 버킷을 만든다.
+func (fetcher *Fetcher) policy(host string) HostPolicy {
+	var matched *HostPolicy
+	for i, policy := range fetcher.config.Hosts {
+		if host != policy.Host && !strings.HasSuffix(host, "."+policy.Host) {
+			continue
+		}
+		if matched == nil || len(policy.Host) > len(matched.Host) {
+			matched = &fetcher.config.Hosts[i]
+		}
+	}
+	if matched != nil {
+		policy := *matched
+		if policy.DelayMs <= 0 {
+			policy.DelayMs = fetcher.config.DefaultPolicy.DelayMs
+		}
+		return policy
+	}
+
+	policy := fetcher.config.DefaultPolicy
+	policy.Host = host
+	return policy
+}
@@ -0,0 +1,251 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "Notifier/models"
+)
+
+func newTestFetcher(config FetcherConfig) *Fetcher {
+	if config.DefaultPolicy.DelayMs == 0 {
+		config.DefaultPolicy = HostPolicy{DelayMs: 1, Burst: 100}
+	}
+	config.HTTP.RetryBaseDelayMs = 1
+	return Fetcher{}.New(config, http.DefaultClient)
+}
+
+// GET/HEAD만 429와 5xx에 대해 MaxRetries번 다시 보내고, 그 밖의 응답이나 POST는 한 번만 보낸다.
+func TestFetcherRetriesIdempotentRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		status   int
+		requests int32
+	}{
+		{"GET 429", http.MethodGet, http.StatusTooManyRequests, 3},
+		{"GET 503", http.MethodGet, http.StatusServiceUnavailable, 3},
+		{"HEAD 500", http.MethodHead, http.StatusInternalServerError, 3},
+		{"GET 404", http.MethodGet, http.StatusNotFound, 1},
+		{"POST 503", http.MethodPost, http.StatusServiceUnavailable, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+
+			fetcher := newTestFetcher(FetcherConfig{HTTP: HTTPConfig{MaxRetries: 2}})
+			req, _ := http.NewRequest(test.method, server.URL, nil)
+			resp, err := fetcher.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != test.status || requests.Load() != test.requests {
+				t.Errorf("status %d after %d requests, want %d after %d", resp.StatusCode, requests.Load(), test.status, test.requests)
+			}
+		})
+	}
+}
+
+// 실패한 GET은 다시 보내 성공한 응답을 반환한다.
+func TestFetcherReturnsResponseAfterRetry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(FetcherConfig{HTTP: HTTPConfig{MaxRetries: 3}})
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := fetcher.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || requests.Load() != 2 {
+		t.Errorf("status %d after %d requests, want 200 after 2", resp.StatusCode, requests.Load())
+	}
+}
+
+// 재시도를 기다리는 동안 ctx가 취소되면 바로 돌아온다.
+func TestFetcherStopsRetryingWhenCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	fetcher := Fetcher{}.New(FetcherConfig{DefaultPolicy: HostPolicy{DelayMs: 1, Burst: 100}, HTTP: HTTPConfig{MaxRetries: 5, RetryBaseDelayMs: 60000}}, http.DefaultClient)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err := fetcher.Do(req)
+	if err == nil {
+		t.Fatal("expected the canceled context error")
+	}
+}
+
+// 같은 호스트로 보내는 요청은 DelayMs 간격으로 나간다.
+func TestFetcherRateLimitsPerHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(FetcherConfig{DefaultPolicy: HostPolicy{DelayMs: 50, Burst: 1}})
+	startedAt := time.Now()
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := fetcher.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if elapsed := time.Since(startedAt); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests took %v, want at least 2 delays of 50ms", elapsed)
+	}
+}
+
+// 정책의 하위 도메인들은 버킷 하나를 함께 쓰고, 정책이 없는 호스트는 따로 버킷을 쓴다.
+func TestFetcherSharesLimiterAcrossSubdomains(t *testing.T) {
+	fetcher := newTestFetcher(FetcherConfig{Hosts: []HostPolicy{{Host: "ajou.ac.kr", DelayMs: 500, Burst: 2}}})
+
+	shared := fetcher.limiter("ajou.ac.kr")
+	if fetcher.limiter("www.ajou.ac.kr") != shared || fetcher.limiter("sw.ajou.ac.kr") != shared {
+		t.Error("subdomains of ajou.ac.kr use separate limiters")
+	}
+	if shared.Burst() != 2 || shared.Limit() != 2 {
+		t.Errorf("limiter = (burst %d, limit %v), want burst 2 every 500ms", shared.Burst(), shared.Limit())
+	}
+	other := fetcher.limiter("example.com")
+	if other == shared || other != fetcher.limiter("example.com") {
+		t.Error("hosts without a policy do not get their own limiter")
+	}
+	if fetcher.limiter("notajou.ac.kr") == shared {
+		t.Error("notajou.ac.kr matched the ajou.ac.kr policy")
+	}
+}
+
+// 호스트와 관계없이 동시에 보내는 요청은 MaxInFlight개를 넘지 않는다.
+func TestFetcherCapsInFlightRequests(t *testing.T) {
+	var current, peak atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := current.Add(1)
+		for {
+			old := peak.Load()
+			if now <= old || peak.CompareAndSwap(old, now) {
+				break
+			}
+		}
+		<-release
+		current.Add(-1)
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher(FetcherConfig{MaxInFlight: 2})
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			resp, err := fetcher.Do(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+	}
+	for current.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if peak.Load() != 2 {
+		t.Errorf("peak in-flight requests = %d, want 2", peak.Load())
+	}
+}
+
+func TestFetcherStartDelay(t *testing.T) {
+	if delay := newTestFetcher(FetcherConfig{}).StartDelay(); delay != 0 {
+		t.Errorf("delay without jitter = %v, want 0", delay)
+	}
+
+	fetcher := newTestFetcher(FetcherConfig{StartJitterMs: 100})
+	distinct := make(map[time.Duration]bool)
+	for i := 0; i < 20; i++ {
+		delay := fetcher.StartDelay()
+		if delay < 0 || delay >= 100*time.Millisecond {
+			t.Fatalf("delay = %v, want within [0, 100ms)", delay)
+		}
+		distinct[delay] = true
+	}
+	if len(distinct) < 2 {
+		t.Errorf("20 delays were all %v, want them spread out", distinct)
+	}
+}
+
+// 목록 페이지의 ETag/Last-Modified를 다음 요청에 붙이고, 304면 ErrNotModified를 반환한다.
+// Forget 뒤에는 조건 없이 다시 받는다.
+func TestNewDocumentFromPageIfModifiedUsesValidators(t *testing.T) {
+	const lastModified = "Tue, 20 Aug 2024 01:00:00 GMT"
+	var etags, modifiedSince []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etags = append(etags, r.Header.Get("If-None-Match"))
+		modifiedSince = append(modifiedSince, r.Header.Get("If-Modified-Since"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte("<html><body><p>목록</p></body></html>"))
+	}))
+	defer server.Close()
+
+	previous := PageFetcher
+	PageFetcher = newTestFetcher(FetcherConfig{})
+	t.Cleanup(func() { PageFetcher = previous })
+
+	ctx := context.Background()
+	doc, err := NewDocumentFromPageIfModified(ctx, server.URL)
+	if err != nil || doc.Find("p").Text() != "목록" {
+		t.Fatalf("first fetch = %v, want the page", err)
+	}
+	_, err = NewDocumentFromPageIfModified(ctx, server.URL)
+	if !errors.Is(err, ErrNotModified) {
+		t.Fatalf("second fetch error = %v, want ErrNotModified", err)
+	}
+	if etags[1] != `"v1"` || modifiedSince[1] != lastModified {
+		t.Errorf("second request validators = (%q, %q), want the first response's ETag and Last-Modified", etags[1], modifiedSince[1])
+	}
+
+	// 처리에 실패한 목록 페이지는 다음 실행에서 304로 건너뛰지 않는다.
+	PageFetcher.Forget(server.URL)
+	doc, err = NewDocumentFromPageIfModified(ctx, server.URL)
+	if err != nil || doc.Find("p").Text() != "목록" {
+		t.Fatalf("fetch after Forget = %v, want the page", err)
+	}
+	if etags[2] != "" || modifiedSince[2] != "" {
+		t.Errorf("request after Forget sent validators (%q, %q)", etags[2], modifiedSince[2])
+	}
+
+	// 상세 페이지처럼 조건 없이 읽는 요청에는 붙이지 않는다.
+	_, err = NewDocumentFromPage(ctx, server.URL)
+	if err != nil || etags[3] != "" {
+		t.Errorf("unconditional fetch = (%v, If-None-Match %q), want the page without validators", err, etags[3])
+	}
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	. "Notifier/models"
+)
+
+const defaultUserAgent = "Mozilla/5.0 (compatible; AjouEventCrawler/1.0)"
+
+// 페이지 요청과 웹훅이 함께 쓰는 HTTP 클라이언트. main에서 설정 파일로 다시 만든다.
+var HTTPClient = NewHTTPClient(HTTPConfig{})
+
+// 연결/응답 대기 시간 제한과 keep-alive 연결 재사용을 설정한 클라이언트를 만든다.
+func NewHTTPClient(config HTTPConfig) *http.Client {
+	config = withHTTPDefaults(config)
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   time.Duration(config.ConnectTimeoutMs) * time.Millisecond,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   time.Duration(config.ConnectTimeoutMs) * time.Millisecond,
+		ResponseHeaderTimeout: time.Duration(config.ReadTimeoutMs) * time.Millisecond,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		ForceAttemptHTTP2:     true,
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(config.RequestTimeoutMs) * time.Millisecond,
+	}
+}
+
+func withHTTPDefaults(config HTTPConfig) HTTPConfig {
+	if config.UserAgent == "" {
+		config.UserAgent = defaultUserAgent
+	}
+	if config.ConnectTimeoutMs <= 0 {
+		config.ConnectTimeoutMs = 5000
+	}
+	if config.ReadTimeoutMs <= 0 {
+		config.ReadTimeoutMs = 15000
+	}
+	if config.RequestTimeoutMs <= 0 {
+		config.RequestTimeoutMs = 30000
+	}
+	if config.MaxIdleConnsPerHost <= 0 {
+		config.MaxIdleConnsPerHost = 4
+	}
+	if config.RetryBaseDelayMs <= 0 {
+		config.RetryBaseDelayMs = 1000
+	}
+	return config
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// JSON lines 형식의 구조화 로거. InitLogger를 호출하기 전에는 stdout으로 출력한다.
+var Logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+type LogConfig struct {
+	Path           string
+	Level          slog.Level
+	Stdout         bool
+	MaxSizeMB      int
+	MaxBackups     int
+	MaxAgeDays     int
+	RotateInterval time.Duration
+}
+
+// LOG_* 환경변수에서 로그 설정을 읽는다. 값이 없으면 기본값을 쓴다.
+func LoadLogConfig() LogConfig {
+	config := LogConfig{
+		Path:           "logs/crawler.log",
+		Level:          slog.LevelInfo,
+		Stdout:         os.Getenv("LOG_STDOUT") == "true",
+		MaxSizeMB:      envInt("LOG_MAX_SIZE_MB", 100),
+		MaxBackups:     envInt("LOG_MAX_BACKUPS", 14),
+		MaxAgeDays:     envInt("LOG_MAX_AGE_DAYS", 30),
+		RotateInterval: time.Duration(envInt("LOG_ROTATE_HOURS", 24)) * time.Hour,
+	}
+	if path := os.Getenv("LOG_PATH"); path != "" {
+		config.Path = path
+	}
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		err := config.Level.UnmarshalText([]byte(strings.ToUpper(level)))
+		if err != nil {
+			Logger.Warn("Invalid LOG_LEVEL, using INFO", "value", level)
+		}
+	}
+	return config
+}
+
+// 크기와 시간 기준으로 회전하는 파일에 기록하는 Logger를 설정한다.
+// 반환된 Closer를 닫으면 남은 로그를 파일에 쓰고 회전을 멈춘다.
+func InitLogger(config LogConfig) io.Closer {
+	file := &lumberjack.Logger{
+		Filename:   config.Path,
+		MaxSize:    config.MaxSizeMB,
+		MaxBackups: config.MaxBackups,
+		MaxAge:     config.MaxAgeDays,
+		LocalTime:  true,
+	}
+
+	var writer io.Writer = file
+	if config.Stdout {
+		writer = io.MultiWriter(file, os.Stdout)
+	}
+	Logger = slog.New(slog.NewJSONHandler(writer, &slog.HandlerOptions{Level: config.Level}))
+
+	done := make(chan struct{})
+	if config.RotateInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(config.RotateInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					if err := file.Rotate(); err != nil {
+						Logger.Error("Failed to rotate log file", "error", err)
+					}
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	return closerFunc(func() error {
+		close(done)
+		return file.Close()
+	})
+}
+
+// 크롤링 한 번을 처음부터 끝까지 따라갈 수 있도록 붙이는 식별자
+func NewRunId() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+type loggerKey struct{}
+
+// 크롤링 한 번에 쓰는 로거를 context에 담는다.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// context에 담긴 로거를 꺼낸다. 없으면 Logger를 반환한다.
+func LoggerFrom(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return Logger
+}
+
+// 에러를 기록하고 panic한다.
+func LogPanic(msg string, err error, args ...any) {
+	Logger.Error(msg, append(args, "error", err)...)
+	panic(err)
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// /metrics로 노출하는 Prometheus 지표. topic은 englishTopic, type은 게시판 템플릿 타입, sink는 config/sinks.json의 name이다.
+var (
+	CrawlRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "crawler_crawl_runs_total",
+		Help: "Number of crawl runs by result.",
+	}, []string{"topic", "type", "result"})
+
+	FetchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "crawler_fetch_duration_seconds",
+		Help:    "Latency of NewDocumentFromPage for list and detail pages.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"topic", "type", "page"})
+
+	FetchWaitDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "crawler_fetch_wait_seconds",
+		Help:    "Time spent waiting for the per-host rate limit and the global in-flight limit.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"host"})
+
+	FetchResponses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "crawler_fetch_responses_total",
+		Help: "Page fetches by HTTP status code, or \"error\" when no response was received.",
+	}, []string{"topic", "type", "page", "code"})
+
+	HTMLStructureChanged = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "crawler_html_structure_changed_total",
+		Help: "Number of list pages that failed the board template check.",
+	}, []string{"topic", "type"})
+
+	NoticesScraped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "crawler_notices_scraped_total",
+		Help: "Number of new notices scraped by row kind (box or num).",
+	}, []string{"topic", "type", "kind"})
+
+	NoticeEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "crawler_notice_events_total",
+		Help: "Number of events detected on already delivered notices (updated, unpinned, deleted).",
+	}, []string{"topic", "type", "event"})
+
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "crawler_webhook_deliveries_total",
+		Help: "Number of notice deliveries to a sink by result.",
+	}, []string{"topic", "type", "sink", "result"})
+
+	WebhookDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "crawler_webhook_duration_seconds",
+		Help:    "Latency of notice deliveries to a sink.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"topic", "type", "sink"})
+
+	RedisTokenErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "crawler_redis_token_errors_total",
+		Help: "Number of failures to read crawling-token from Redis.",
+	})
+)
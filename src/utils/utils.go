@@ -3,24 +3,31 @@ package utils
 import (
     "bytes"
     "context"
-    "database/sql"
     "encoding/json"
+    "errors"
     "io"
     "log"
     "net/http"
-    "github.com/go-redis/redis/v8"
     "os"
     "fmt"
+    "time"
     . "Notifier/models"
+    "Notifier/src/signature"
     "github.com/PuerkitoBio/goquery"
-    "github.com/go-sql-driver/mysql"
 )
 
-var ErrorLogger *log.Logger
-var SentNoticeLogger *log.Logger
-var PostLogger *log.Logger
-var DB *sql.DB
-var ctx = context.Background()
+// 비어 있지 않으면 웹훅 본문을 HMAC-SHA256으로 서명한다. main에서 WEBHOOK_SECRET으로 설정한다.
+var WebhookSecret []byte
+
+// 200이 아닌 응답을 받았을 때 반환하는 에러
+type StatusCodeError struct {
+    StatusCode int
+    URL        string
+}
+
+func (err *StatusCodeError) Error() string {
+    return fmt.Sprintf("status code error: %d, URL: %s", err.StatusCode, err.URL)
+}
 
 func CreateDir(path string) {
     _, err := os.Stat(path)
@@ -32,57 +39,6 @@ func CreateDir(path string) {
     }
 }
 
-// Redis에서 crawling-token 가져오기
-func GetTokenFromRedis() string {
-    // Redis 클라이언트 설정
-    redisHost := os.Getenv("REDIS_HOST")
-    redisPort := os.Getenv("REDIS_PORT")
-
-    rdb := redis.NewClient(&redis.Options{
-        Addr: redisHost + ":" + redisPort, // 환경변수에서 Redis 호스트 정보 가져오기
-    })
-
-    // crawling-token 키로 Redis에서 토큰 가져오기
-    token, err := rdb.Get(ctx, "crawling-token").Result()
-    if err != nil {
-        log.Fatalf("Failed to get token from Redis: %v", err)
-    }
-    return token
-}
-
-func OpenLogFile(path string) *os.File {
-    file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0777)
-    if err != nil {
-        log.Fatal(err)
-    }
-    return file
-}
-
-func CreateLogger(file *os.File) *log.Logger {
-    return log.New(file, "", log.Ldate|log.Ltime|log.Lshortfile)
-}
-
-func ConnectDB() *sql.DB {
-    config := mysql.Config{
-        User:                 os.Getenv("DB_USER"),
-        Passwd:               os.Getenv("DB_PW"),
-        Net:                  "tcp",
-        Addr:                 os.Getenv("DB_IP") + ":" + os.Getenv("DB_PORT"),
-        DBName:               os.Getenv("DB_NAME"),
-        AllowNativePasswords: true,
-    }
-    connector, err := mysql.NewConnector(&config)
-    if err != nil {
-        ErrorLogger.Panic(err)
-    }
-    db := sql.OpenDB(connector)
-    err = db.Ping()
-    if err != nil {
-        ErrorLogger.Panic(err)
-    }
-    return db
-}
-
 func LoadNotifierConfig(path string) []NotifierConfig {
     file, err := os.Open(path)
     if err != nil {
@@ -98,112 +54,159 @@ func LoadNotifierConfig(path string) []NotifierConfig {
     return configs
 }
 
-func LoadDbData(topic string) (int, int) {
-    var boxCount int
-    query := "SELECT n.value FROM notice AS n JOIN topic AS t ON n.topic_id = t.id WHERE t.department = ? AND n.type = ?"
-
-    err := DB.QueryRow(query, topic, "box").Scan(&boxCount)
+func LoadBoardTemplates(path string) map[int]BoardTemplate {
+    file, err := os.Open(path)
     if err != nil {
-        log.Fatal(err)
+            log.Fatal(err)
     }
-
-    var maxNum int
-    err = DB.QueryRow(query, topic, "num").Scan(&maxNum)
+    defer file.Close()
+    var templates []BoardTemplate
+    decoder := json.NewDecoder(file)
+    err = decoder.Decode(&templates)
     if err != nil {
-        log.Fatal(err)
+            log.Fatal(err)
+    }
+    templateMap := make(map[int]BoardTemplate, len(templates))
+    for _, template := range templates {
+        templateMap[template.Type] = template
     }
+    return templateMap
+}
+
+// 웹훅을 호출할 때 Redis에서 가져온 crawling-token을 헤더에 추가
+// WebhookSecret이 있으면 서명 헤더도 붙인다. 2xx 응답을 받지 못하면 에러를 반환한다.
+// 토큰을 읽지 못해도 에러만 반환하므로 OutboxWorker가 나중에 다시 보낸다.
+func SendCrawlingWebhook(ctx context.Context, url string, payload any) error {
+    return sendCrawlingWebhook(ctx, url, "", payload)
+}
 
-    return boxCount, maxNum
+// 이미 보낸 공지의 이벤트를 보낸다. X-Crawler-Event 헤더에 이벤트 이름을 담는다.
+func SendCrawlingEvent(ctx context.Context, url string, event string, payload any) error {
+    return sendCrawlingWebhook(ctx, url, event, payload)
 }
 
-// 웹훅을 호출할 때 Redis에서 가져온 토큰을 Bearer로 헤더에 추가
-func SendCrawlingWebhook(url string, payload any) {
+func sendCrawlingWebhook(ctx context.Context, url string, event string, payload any) error {
     payloadJson, err := json.Marshal(payload)
     if err != nil {
-        ErrorLogger.Panic(err)
+        return err
     }
-	buff := bytes.NewBuffer(payloadJson)
 
-    // Redis에서 crawling-token 가져오기
-    token := GetTokenFromRedis()
+    for attempt := 0; ; attempt++ {
+        // Redis에서 crawling-token 가져오기
+        token, err := CrawlingToken.Token(ctx)
+        if err != nil {
+            return fmt.Errorf("failed to get crawling token: %w", err)
+        }
+
+        statusCode, err := postWebhook(ctx, url, event, payloadJson, token)
+        if err != nil {
+            return err
+        }
+
+        // 토큰이 바뀌었을 수 있으므로 한 번만 새 토큰으로 다시 보낸다.
+        if (statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden) && attempt == 0 {
+            CrawlingToken.Invalidate()
+            continue
+        }
+        if statusCode < 200 || statusCode >= 300 {
+            return fmt.Errorf("webhook status code error: %d, URL: %s", statusCode, url)
+        }
+        return nil
+    }
+}
 
+func postWebhook(ctx context.Context, url string, event string, payloadJson []byte, token string) (int, error) {
     // HTTP 요청 생성
-    req, err := http.NewRequest("POST", url, buff)
+    req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payloadJson))
     if err != nil {
-        ErrorLogger.Panic(err)
+        return 0, err
     }
 
     // Content-Type 헤더 설정
     req.Header.Set("Content-Type", "application/json")
 
-    // Authorization 헤더에 Bearer 토큰 설정
+    // crawling-token 헤더 설정
     req.Header.Set("crawling-token", token)
 
+    if event != "" {
+        req.Header.Set("X-Crawler-Event", event)
+    }
+
+    // 토큰만으로는 공지를 보낼 수 없도록 본문 서명
+    if len(WebhookSecret) > 0 {
+        signature.SignRequest(req, WebhookSecret, time.Now(), payloadJson)
+    }
 
-    // HTTP 클라이언트로 요청 보내기
-    client := &http.Client{}
-    resp, err := client.Do(req)
+    // 공용 HTTP 클라이언트로 요청 보내기
+    resp, err := HTTPClient.Do(req)
     if err != nil {
-        ErrorLogger.Panic(err)
+        return 0, err
     }
     defer resp.Body.Close()
 
     // 응답 본문 읽기
     body, err := io.ReadAll(resp.Body)
-	if err != nil {
-        ErrorLogger.Panic(err)
+    if err != nil {
+        return 0, err
     }
-    PostLogger.Println(string(body))
+    LoggerFrom(ctx).Info("Webhook response", "url", url, "status", resp.StatusCode, "body", string(body))
+    return resp.StatusCode, nil
 }
 
-func GetNumNoticeCountReference(doc *goquery.Document, englishTopic, boxNoticeSelector string) int {
-    if englishTopic != "Software" {
-        return 10
-    }
-    boxNoticeSels := doc.Find(boxNoticeSelector)
-    boxCount := boxNoticeSels.Length()
-    return 15 - boxCount
+// 조건부 GET에서 페이지가 바뀌지 않았을 때 반환하는 에러
+var ErrNotModified = errors.New("not modified")
+
+func NewDocumentFromPage(ctx context.Context, url string) (*goquery.Document, error) {
+    return newDocumentFromPage(ctx, url, false)
+}
+
+// 이전 응답의 ETag/Last-Modified로 조건부 요청을 보낸다. 바뀌지 않았으면 ErrNotModified를 반환한다.
+func NewDocumentFromPageIfModified(ctx context.Context, url string) (*goquery.Document, error) {
+    return newDocumentFromPage(ctx, url, true)
 }
 
-func NewDocumentFromPage(url string) (*goquery.Document, error) {
+func newDocumentFromPage(ctx context.Context, url string, conditional bool) (*goquery.Document, error) {
     // HTTP GET 요청을 위한 새로운 요청 생성
-    req, err := http.NewRequest("GET", url, nil)
+    req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
     if err != nil {
         // 요청 생성 에러 발생 시 에러 반환
-        ErrorLogger.Printf("Request creation error: %s", err)
-        return nil, err
+        return nil, fmt.Errorf("request creation error: %w", err)
     }
 
     // User-Agent 헤더 설정
-    req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36")
+    req.Header.Set("User-Agent", PageFetcher.UserAgent())
+    if conditional {
+        PageFetcher.addValidators(req)
+    }
 
-    // HTTP 클라이언트 생성
-    client := &http.Client{}
-    
-    // 요청 실행
-    resp, err := client.Do(req)
+    // 호스트별 속도 제한을 거쳐 요청 실행
+    resp, err := PageFetcher.Do(req)
     if err != nil {
         // 네트워크 에러 발생 시 에러 반환
-        ErrorLogger.Printf("Network error: %s", err)
-        return nil, err
+        return nil, fmt.Errorf("network error: %w", err)
     }
-        
+
     defer resp.Body.Close()
 
+    if conditional && resp.StatusCode == http.StatusNotModified {
+        return nil, ErrNotModified
+    }
+
     // 응답 상태 코드 확인
     if resp.StatusCode != http.StatusOK {
         // 상태 코드 에러 발생 시 에러 반환
-        ErrorLogger.Printf("Status code error: %d, URL: %s", resp.StatusCode, url)
-        return nil, fmt.Errorf("status code error: %d, URL: %s", resp.StatusCode, url)
+        return nil, &StatusCodeError{StatusCode: resp.StatusCode, URL: url}
     }
 
     // HTML 문서 파싱
     doc, err := goquery.NewDocumentFromReader(resp.Body)
     if err != nil {
         // 파싱 에러 발생 시 에러 반환
-        ErrorLogger.Printf("Error parsing document: %s", err)
-        return nil, err
+        return nil, fmt.Errorf("error parsing document: %w", err)
     }
 
+    if conditional {
+        PageFetcher.saveValidators(url, resp)
+    }
     return doc, nil
-}
\ No newline at end of file
+}